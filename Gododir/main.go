@@ -38,21 +38,80 @@ func tasks(p *do.Project) {
 		if startingNumber < 0 {
 			log.Fatalln("--starting-number required")
 		}
-		context.Start(`go run main.go --environment={{.environment}} --starting-number={{.startingNumber}}`,
-			do.M{"environment": environment, "startingNumber": startingNumber, "$in": "cmd/populate_blocks"})
+		concurrency := context.Args.MayInt(4, "concurrency")
+		rateLimit := context.Args.MayInt(0, "rate-limit")
+		context.Start(`go run main.go --environment={{.environment}} --starting-number={{.startingNumber}} --concurrency={{.concurrency}} --rate-limit={{.rateLimit}}`,
+			do.M{"environment": environment, "startingNumber": startingNumber, "concurrency": concurrency, "rateLimit": rateLimit, "$in": "cmd/populate_blocks"})
 	})
 
 	p.Task("getLogs", nil, func(context *do.Context) {
 		environment := parseEnvironment(context)
 		contractHash := context.Args.MayString("", "contract-hash", "c")
+		allWatched := context.Args.MayBool(false, "all-watched")
+		topic0 := context.Args.MayString("", "topic0")
+		topic1 := context.Args.MayString("", "topic1")
+		topic2 := context.Args.MayString("", "topic2")
+		topic3 := context.Args.MayString("", "topic3")
+		if contractHash == "" && !allWatched && topic0+topic1+topic2+topic3 == "" {
+			log.Fatalln("--contract-hash, --all-watched or a topic filter required")
+		}
+		context.Start(`go run main.go --environment={{.environment}} --contract-hash={{.contractHash}} --all-watched={{.allWatched}} --topic0={{.topic0}} --topic1={{.topic1}} --topic2={{.topic2}} --topic3={{.topic3}}`,
+			do.M{
+				"environment":  environment,
+				"contractHash": contractHash,
+				"allWatched":   allWatched,
+				"topic0":       topic0,
+				"topic1":       topic1,
+				"topic2":       topic2,
+				"topic3":       topic3,
+				"$in":          "cmd/get_logs",
+			})
+	})
+
+	p.Task("createEventTables", nil, func(context *do.Context) {
+		environment := parseEnvironment(context)
+		contractHash := context.Args.MayString("", "contract-hash", "c")
+		tablePrefix := context.Args.MayString("", "table-prefix", "p")
 		if contractHash == "" {
 			log.Fatalln("--contract-hash required")
 		}
-		context.Start(`go run main.go --environment={{.environment}} --contract-hash={{.contractHash}}`,
+		if tablePrefix == "" {
+			log.Fatalln("--table-prefix required")
+		}
+		context.Start(`go run main.go --environment={{.environment}} --contract-hash={{.contractHash}} --table-prefix={{.tablePrefix}}`,
 			do.M{
 				"environment":  environment,
 				"contractHash": contractHash,
-				"$in":          "cmd/get_logs",
+				"tablePrefix":  tablePrefix,
+				"$in":          "cmd/create_event_tables",
+			})
+	})
+
+	p.Task("recordContractState", nil, func(context *do.Context) {
+		environment := parseEnvironment(context)
+		contractHash := context.Args.MayString("", "contract-hash", "c")
+		startingNumber := context.Args.MayInt(-1, "starting-number")
+		endingNumber := context.Args.MayInt(-1, "ending-number")
+		interval := context.Args.MayInt(1, "interval")
+		context.Start(`go run main.go --environment={{.environment}} --contract-hash={{.contractHash}} --starting-number={{.startingNumber}} --ending-number={{.endingNumber}} --interval={{.interval}}`,
+			do.M{
+				"environment":    environment,
+				"contractHash":   contractHash,
+				"startingNumber": startingNumber,
+				"endingNumber":   endingNumber,
+				"interval":       interval,
+				"$in":            "cmd/record_contract_state",
+			})
+	})
+
+	p.Task("serve", nil, func(context *do.Context) {
+		environment := parseEnvironment(context)
+		port := context.Args.MayInt(8080, "port")
+		context.Start(`go run main.go --environment={{.environment}} --port={{.port}}`,
+			do.M{
+				"environment": environment,
+				"port":        port,
+				"$in":         "cmd/serve",
 			})
 	})
 
@@ -96,13 +155,19 @@ func tasks(p *do.Project) {
 		environment := parseEnvironment(context)
 		contractHash := context.Args.MayString("", "contract-hash", "c")
 		blockNumber := context.Args.MayInt(-1, "block-number", "b")
+		attribute := context.Args.MayString("", "attribute")
+		arguments := context.Args.MayString("", "arguments")
+		output := context.Args.MayString("console", "output")
 		if contractHash == "" {
 			log.Fatalln("--contract-hash required")
 		}
-		context.Start(`go run main.go --environment={{.environment}} --contract-hash={{.contractHash}} --block-number={{.blockNumber}}`,
+		context.Start(`go run main.go --environment={{.environment}} --contract-hash={{.contractHash}} --block-number={{.blockNumber}} --attribute={{.attribute}} --arguments={{.arguments}} --output={{.output}}`,
 			do.M{"environment": environment,
 				"contractHash": contractHash,
 				"blockNumber":  blockNumber,
+				"attribute":    attribute,
+				"arguments":    arguments,
+				"output":       output,
 				"$in":          "cmd/show_contract_summary"})
 	})
 
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/vulcanize/vulcanizedb/cmd"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+)
+
+func main() {
+	environment := flag.String("environment", "", "Environment name")
+	contractHash := flag.String("contract-hash", "", "Contract hash to create event tables for")
+	tablePrefix := flag.String("table-prefix", "", "Prefix for the event table names, e.g. token")
+	flag.Parse()
+	if *tablePrefix == "" {
+		log.Fatalln("--table-prefix required")
+	}
+
+	config := cmd.LoadConfig(*environment)
+	blockchain := cmd.LoadBlockchain(config.Client)
+	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
+	contract, err := repository.FindContract(*contractHash)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	contractAbi, err := geth.ParseAbi(contract.Abi)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	undecodedLogs := repository.FindUndecodedLogs(*contractHash)
+	err = repository.CreateDecodedEvents(geth.DecodeLogs(contractAbi, undecodedLogs))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	eventTables, err := geth.EventTables(*tablePrefix, contract)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, eventTable := range eventTables {
+		err = repository.CreateEventTable(eventTable)
+		if err != nil {
+			log.Fatalf("Error creating %s\n%v", eventTable.Name, err)
+		}
+		log.Println("Created event table:", eventTable.Name)
+	}
+}
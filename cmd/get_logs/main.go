@@ -1,24 +1,143 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"flag"
 
 	"math/big"
+	"strings"
 
 	"time"
 
 	"github.com/vulcanize/vulcanizedb/cmd"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 )
 
-func min(a, b int64) int64 {
-	if a < b {
-		return a
+func saveLogs(repository repositories.Postgres, contractHash string, contractAbi *abi.ABI, logs []core.Log) error {
+	err := repository.CreateLogs(logs)
+	if err != nil {
+		return err
+	}
+	if contractAbi == nil {
+		return nil
+	}
+	err = repository.CreateDecodedEvents(geth.DecodeLogs(*contractAbi, logs))
+	if err != nil {
+		return err
+	}
+	err = repository.UpdateEventTables(contractHash)
+	if err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func logBackfillProgress(logRange core.LogRange, err error) {
+	if err != nil {
+		log.Printf("Error backfilling logs of %s in blocks %d - %d, will retry on the next run\n%v", logRange.ContractHash, logRange.StartingBlockNumber, logRange.EndingBlockNumber, err)
+		return
+	}
+	log.Printf("Backfilled logs of %s in blocks %d - %d\n", logRange.ContractHash, logRange.StartingBlockNumber, logRange.EndingBlockNumber)
+}
+
+func contractAbi(contract core.Contract) *abi.ABI {
+	if contract.Abi == "" {
+		log.Println("No abi for contract, logs will not be decoded:", contract.Hash)
+		return nil
+	}
+	contractAbi, err := geth.ParseAbi(contract.Abi)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	return &contractAbi
+}
+
+// parseTopics reads the values accepted at each topic position, separated by
+// commas.
+func parseTopics(positions ...string) [][]string {
+	var topics [][]string
+	for _, position := range positions {
+		var values []string
+		for _, value := range strings.Split(position, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		topics = append(topics, values)
+	}
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+	return topics
+}
+
+// contractsToIngest returns the contracts whose logs are saved, where a
+// contract without a hash stands for every contract matching the topics.
+func contractsToIngest(repository repositories.Repository, contractHash string, allWatched bool, topics [][]string) []core.Contract {
+	switch {
+	case allWatched:
+		return repository.FindWatchedContracts()
+	case contractHash != "":
+		contract, err := repository.FindContract(contractHash)
+		if err != nil {
+			return []core.Contract{{Hash: contractHash}}
+		}
+		return []core.Contract{contract}
+	case len(topics) > 0:
+		return []core.Contract{{}}
+	}
+	log.Fatalln("--contract-hash, --all-watched or a topic filter required")
+	return nil
+}
+
+type ingestedContract struct {
+	contract    core.Contract
+	contractAbi *abi.ABI
+}
+
+func (ingested ingestedContract) saveLogs(repository repositories.Postgres, logs []core.Log) error {
+	return saveLogs(repository, ingested.contract.Hash, ingested.contractAbi, logs)
+}
+
+func updateLogsWindow(ctx context.Context, blockchain core.Blockchain, repository repositories.Postgres, contracts []ingestedContract, topics [][]string) {
+	lastBlock, err := blockchain.LastBlock(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	z := &big.Int{}
+	z.Sub(lastBlock, big.NewInt(25))
+	log.Printf("Logs Window: %d - %d", z.Int64(), lastBlock.Int64())
+	filter := core.LogFilter{Topics: topics}
+	for _, ingested := range contracts {
+		if ingested.contract.Hash != "" {
+			filter.Addresses = append(filter.Addresses, ingested.contract.Hash)
+		}
+	}
+	logs, err := blockchain.FilterLogs(ctx, filter, z, lastBlock)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, ingested := range contracts {
+		var contractLogs []core.Log
+		for _, log := range logs {
+			if ingested.contract.Hash == "" || strings.EqualFold(log.Address, ingested.contract.Hash) {
+				contractLogs = append(contractLogs, log)
+			}
+		}
+		err = ingested.saveLogs(repository, contractLogs)
+		if err != nil {
+			log.Println(err)
+		}
 	}
-	return b
 }
 
 const (
@@ -28,44 +147,54 @@ const (
 
 func main() {
 	environment := flag.String("environment", "", "Environment name")
-	contractHash := flag.String("contract-hash", "", "Contract hash to show summary")
-	ticker := time.NewTicker(pollingInterval)
-	defer ticker.Stop()
-
+	contractHash := flag.String("contract-hash", "", "Contract hash to retrieve the logs of")
+	allWatched := flag.Bool("all-watched", false, "Retrieve the logs of every watched contract")
+	topic0 := flag.String("topic0", "", "Comma separated values accepted as the first topic, e.g. an event signature hash")
+	topic1 := flag.String("topic1", "", "Comma separated values accepted as the second topic, e.g. an indexed address")
+	topic2 := flag.String("topic2", "", "Comma separated values accepted as the third topic")
+	topic3 := flag.String("topic3", "", "Comma separated values accepted as the fourth topic")
 	flag.Parse()
 
 	config := cmd.LoadConfig(*environment)
-	blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+	blockchain := cmd.LoadBlockchain(config.Client)
 	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
+	topics := parseTopics(*topic0, *topic1, *topic2, *topic3)
+	var contracts []ingestedContract
+	for _, contract := range contractsToIngest(repository, *contractHash, *allWatched, topics) {
+		ingested := ingestedContract{contract: contract}
+		if contract.Hash != "" {
+			ingested.contractAbi = contractAbi(contract)
+		}
+		contracts = append(contracts, ingested)
+	}
+	if len(contracts) == 0 {
+		log.Fatalln("No watched contracts")
+	}
 
-	lastBlockNumber := blockchain.LastBlock().Int64()
-	stepSize := int64(1000)
-
-	go func() {
-		for i := int64(0); i < lastBlockNumber; i = min(i+stepSize, lastBlockNumber) {
-			logs, err := blockchain.GetLogs(core.Contract{Hash: *contractHash}, big.NewInt(i), big.NewInt(i+stepSize))
-			log.Println("Backfilling Logs:", i)
+	lifecycle := cmd.NewLifecycle()
+	lastBlock, err := blockchain.LastBlock(lifecycle.Context())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	lifecycle.Go("backfilling logs", func(ctx context.Context) error {
+		for _, ingested := range contracts {
+			ingested := ingested
+			backfiller := history.NewLogBackfiller(blockchain, repository, func(logs []core.Log) error {
+				return ingested.saveLogs(repository, logs)
+			})
+			backfiller.Topics = topics
+			backfiller.Progress = logBackfillProgress
+			err := backfiller.Backfill(ctx, ingested.contract.Hash, ingested.contract.DeploymentBlock, lastBlock.Int64())
 			if err != nil {
-				log.Println(err)
+				return err
 			}
-			repository.CreateLogs(logs)
 		}
-	}()
-
-	done := make(chan struct{})
-	go func() { done <- struct{}{} }()
-	for range ticker.C {
-		select {
-		case <-done:
-			go func() {
-				z := &big.Int{}
-				z.Sub(blockchain.LastBlock(), big.NewInt(25))
-				log.Printf("Logs Window: %d - %d", z.Int64(), blockchain.LastBlock().Int64())
-				logs, _ := blockchain.GetLogs(core.Contract{Hash: *contractHash}, z, blockchain.LastBlock())
-				repository.CreateLogs(logs)
-				done <- struct{}{}
-			}()
-		default:
-		}
-	}
+		return nil
+	})
+	lifecycle.Go("updating the logs window", func(ctx context.Context) error {
+		return cmd.Every(ctx, pollingInterval, func() {
+			updateLogsWindow(ctx, blockchain, repository, contracts, topics)
+		})
+	})
+	cmd.Exit(lifecycle.Wait())
 }
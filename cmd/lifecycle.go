@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Lifecycle runs the long-lived tasks of a command with a root context that
+// is cancelled on SIGINT or SIGTERM. Each task finishes its current unit of
+// work and returns when the context is done; a second signal exits at once.
+type Lifecycle struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	signals chan os.Signal
+	tasks   sync.WaitGroup
+	mutex   sync.Mutex
+	err     error
+}
+
+func NewLifecycle() *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	lifecycle := &Lifecycle{
+		ctx:     ctx,
+		cancel:  cancel,
+		signals: make(chan os.Signal, 2),
+	}
+	signal.Notify(lifecycle.signals, syscall.SIGINT, syscall.SIGTERM)
+	go lifecycle.handleSignals()
+	return lifecycle
+}
+
+func (lifecycle *Lifecycle) Context() context.Context {
+	return lifecycle.ctx
+}
+
+// Go runs the task in the background. A task that fails cancels the context,
+// shutting down the others.
+func (lifecycle *Lifecycle) Go(name string, task func(ctx context.Context) error) {
+	lifecycle.tasks.Add(1)
+	go func() {
+		defer lifecycle.tasks.Done()
+		err := task(lifecycle.ctx)
+		if err != nil && err != context.Canceled {
+			lifecycle.fail(fmt.Errorf("%s: %v", name, err))
+		}
+	}()
+}
+
+// Wait returns when every task has returned, with the error of the first
+// task that failed.
+func (lifecycle *Lifecycle) Wait() error {
+	lifecycle.tasks.Wait()
+	signal.Stop(lifecycle.signals)
+	lifecycle.cancel()
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	return lifecycle.err
+}
+
+func (lifecycle *Lifecycle) fail(err error) {
+	lifecycle.mutex.Lock()
+	if lifecycle.err == nil {
+		lifecycle.err = err
+	}
+	lifecycle.mutex.Unlock()
+	lifecycle.cancel()
+}
+
+func (lifecycle *Lifecycle) handleSignals() {
+	select {
+	case received := <-lifecycle.signals:
+		log.Printf("Received %v, finishing current work before exiting\n", received)
+		lifecycle.cancel()
+	case <-lifecycle.ctx.Done():
+		return
+	}
+	received := <-lifecycle.signals
+	log.Printf("Received %v again, exiting immediately\n", received)
+	os.Exit(1)
+}
+
+// Exit ends the command with status 1 if err is set, 0 otherwise.
+func Exit(err error) {
+	if err != nil {
+		log.Printf("Exiting after error\n%v", err)
+		os.Exit(1)
+	}
+	log.Println("Shut down cleanly")
+	os.Exit(0)
+}
+
+// Every runs work straight away and then interval after each run finishes,
+// until the context is done, returning once the run in progress does.
+func Every(ctx context.Context, interval time.Duration, work func()) error {
+	for {
+		work()
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
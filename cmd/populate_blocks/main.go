@@ -1,22 +1,32 @@
 package main
 
 import (
+	"context"
 	"flag"
 
 	"fmt"
 
 	"github.com/vulcanize/vulcanizedb/cmd"
-	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/history"
 )
 
 func main() {
 	environment := flag.String("environment", "", "Environment name")
 	startingBlockNumber := flag.Int("starting-number", -1, "First block to fill from")
+	concurrency := flag.Int("concurrency", 4, "Number of blocks to fetch at once")
+	rateLimit := flag.Int("rate-limit", 0, "Maximum calls per second to the node, counting each call in a batch, 0 for no limit")
 	flag.Parse()
 	config := cmd.LoadConfig(*environment)
-	blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+	blockchain := cmd.LoadBlockchain(config.Client)
+	blockchain.LimitRate(*rateLimit)
 	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
-	numberOfBlocksCreated := history.PopulateMissingBlocks(blockchain, repository, int64(*startingBlockNumber))
-	fmt.Printf("Populated %d blocks", numberOfBlocksCreated)
+	backfiller := history.NewBackfiller(blockchain, repository, *concurrency)
+	backfiller.Progress = cmd.LogBackfillProgress
+	lifecycle := cmd.NewLifecycle()
+	lifecycle.Go("populating missing blocks", func(ctx context.Context) error {
+		numberOfBlocksCreated := backfiller.PopulateMissingBlocks(ctx, int64(*startingBlockNumber))
+		fmt.Printf("Populated %d blocks\n", numberOfBlocksCreated)
+		return ctx.Err()
+	})
+	cmd.Exit(lifecycle.Wait())
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/vulcanize/vulcanizedb/cmd"
+	"github.com/vulcanize/vulcanizedb/pkg/contract_state"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+func main() {
+	environment := flag.String("environment", "", "Environment name")
+	contractHash := flag.String("contract-hash", "", "Contract hash to record state for, defaults to every watched contract")
+	startingNumber := flag.Int64("starting-number", -1, "First block number to record state at, defaults to the block the contract was deployed in")
+	endingNumber := flag.Int64("ending-number", -1, "Last block number to record state at, defaults to the last block")
+	interval := flag.Int64("interval", 1, "Number of blocks between recorded states")
+	flag.Parse()
+	if *interval < 1 {
+		log.Fatalln("--interval must be at least 1")
+	}
+
+	config := cmd.LoadConfig(*environment)
+	blockchain := cmd.LoadBlockchain(config.Client)
+	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
+	lifecycle := cmd.NewLifecycle()
+	if *endingNumber < 0 {
+		lastBlock, err := blockchain.LastBlock(lifecycle.Context())
+		if err != nil {
+			log.Fatalln(err)
+		}
+		*endingNumber = lastBlock.Int64()
+	}
+
+	var contracts []core.Contract
+	if *contractHash == "" {
+		contracts = repository.FindWatchedContracts()
+	} else {
+		contract, err := repository.FindContract(*contractHash)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		contracts = append(contracts, contract)
+	}
+	lifecycle.Go("recording contract state", func(ctx context.Context) error {
+		for _, contract := range contracts {
+			startingBlockNumber := *startingNumber
+			if startingBlockNumber < 0 {
+				startingBlockNumber = contract.DeploymentBlock
+			}
+			recorded, err := contract_state.BackfillContractState(ctx, blockchain, repository, contract, startingBlockNumber, *endingNumber, *interval)
+			log.Printf("Recorded state of %s at %d blocks\n", contract.Hash, recorded)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	cmd.Exit(lifecycle.Wait())
+}
@@ -2,13 +2,13 @@ package main
 
 import (
 	"fmt"
+	"log"
 
 	"flag"
 
 	"github.com/vulcanize/vulcanizedb/cmd"
 	"github.com/vulcanize/vulcanizedb/pkg/blockchain_listener"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
-	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/observers"
 )
 
@@ -17,14 +17,22 @@ func main() {
 	flag.Parse()
 	config := cmd.LoadConfig(*environment)
 	fmt.Printf("Creating Geth Blockchain to: %s\n", config.Client.IPCPath)
-	blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+	blockchain := cmd.LoadBlockchain(config.Client)
 	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
-	listener := blockchain_listener.NewBlockchainListener(
+	lifecycle := cmd.NewLifecycle()
+	listener, err := blockchain_listener.NewBlockchainListener(
+		lifecycle.Context(),
 		blockchain,
 		[]core.BlockchainObserver{
 			observers.BlockchainLoggingObserver{},
-			observers.NewBlockchainDbObserver(repository),
+			observers.NewBlockchainDbObserver(blockchain, repository),
 		},
 	)
-	listener.Start()
+	if err != nil {
+		log.Fatalf("Error subscribing to new blocks\n%v", err)
+	}
+	lifecycle.Go("listening for new blocks", listener.Start)
+	err = lifecycle.Wait()
+	listener.Stop()
+	cmd.Exit(err)
 }
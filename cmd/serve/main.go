@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/cmd"
+	"github.com/vulcanize/vulcanizedb/pkg/api"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	environment := flag.String("environment", "", "Environment name")
+	port := flag.Int("port", 8080, "Port to serve the HTTP API on")
+	flag.Parse()
+
+	config := cmd.LoadConfig(*environment)
+	blockchain := cmd.LoadBlockchain(config.Client)
+	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
+
+	address := fmt.Sprintf(":%d", *port)
+	server := &http.Server{Addr: address, Handler: api.NewServer(blockchain, repository)}
+	lifecycle := cmd.NewLifecycle()
+	lifecycle.Go("serving the HTTP API", func(ctx context.Context) error {
+		return serve(ctx, server)
+	})
+	cmd.Exit(lifecycle.Wait())
+}
+
+// serve lets the requests in progress finish before returning when the
+// context is done.
+func serve(ctx context.Context, server *http.Server) error {
+	stopped := make(chan error, 1)
+	go func() {
+		log.Printf("Serving HTTP API on %s\n", server.Addr)
+		stopped <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-stopped:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
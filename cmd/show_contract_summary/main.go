@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 
 	"log"
@@ -9,23 +10,66 @@ import (
 
 	"github.com/vulcanize/vulcanizedb/cmd"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_summary"
-	"github.com/vulcanize/vulcanizedb/pkg/geth"
 )
 
+// withCalledAttribute adds the method requested with --attribute to the
+// summary presented as json or csv.
+func withCalledAttribute(ctx context.Context, summary contract_summary.ContractSummary, attributeName string, attributeArguments string) contract_summary.ContractSummary {
+	if attributeName == "" {
+		return summary
+	}
+	summary, err := summary.CallAttribute(ctx, attributeName, cmd.ParseArguments(attributeArguments))
+	if err != nil {
+		log.Fatalf("%s: %v\n", attributeName, err)
+	}
+	return summary
+}
+
 func main() {
 	environment := flag.String("environment", "", "Environment name")
 	contractHash := flag.String("contract-hash", "", "Contract hash to show summary")
 	_blockNumber := flag.Int64("block-number", -1, "Block number of summary")
+	attributeName := flag.String("attribute", "", "Constant method to call, e.g. balanceOf")
+	attributeArguments := flag.String("arguments", "", "Comma separated arguments of the method, e.g. 0x1234")
+	outputFormat := flag.String("output", "console", "Output format: console, json or csv")
 	flag.Parse()
 	config := cmd.LoadConfig(*environment)
-	blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+	blockchain := cmd.LoadBlockchain(config.Client)
 	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
 	blockNumber := cmd.RequestedBlockNumber(_blockNumber)
 
-	contractSummary, err := contract_summary.NewSummary(blockchain, repository, *contractHash, blockNumber)
+	ctx := context.Background()
+	contractSummary, err := contract_summary.NewSummary(ctx, blockchain, repository, *contractHash, blockNumber)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	output := contract_summary.GenerateConsoleOutput(contractSummary)
-	fmt.Println(output)
+	switch *outputFormat {
+	case "json":
+		contractSummary = withCalledAttribute(ctx, contractSummary, *attributeName, *attributeArguments)
+		output, err := contract_summary.GenerateJsonOutput(ctx, contractSummary)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Println(output)
+	case "csv":
+		contractSummary = withCalledAttribute(ctx, contractSummary, *attributeName, *attributeArguments)
+		output, err := contract_summary.GenerateCsvOutput(ctx, contractSummary)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Print(output)
+	case "console":
+		output := contract_summary.GenerateConsoleOutput(ctx, contractSummary)
+		fmt.Println(output)
+		if *attributeName != "" {
+			arguments := cmd.ParseArguments(*attributeArguments)
+			output, err := contract_summary.GenerateAttributeConsoleOutput(ctx, contractSummary, *attributeName, arguments)
+			if err != nil {
+				log.Fatalf("%s: %v\n", *attributeName, err)
+			}
+			fmt.Println(output)
+		}
+	default:
+		log.Fatalf("unknown output format %q, expected console, json or csv\n", *outputFormat)
+	}
 }
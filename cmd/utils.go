@@ -9,9 +9,12 @@ import (
 
 	"math/big"
 
+	"strings"
+
 	"github.com/vulcanize/vulcanizedb/pkg/config"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"github.com/vulcanize/vulcanizedb/pkg/repositories"
 )
 
@@ -23,6 +26,14 @@ func LoadConfig(environment string) config.Config {
 	return cfg
 }
 
+func LoadBlockchain(client config.Client) *geth.GethBlockchain {
+	blockchain, err := geth.NewGethBlockchain(client)
+	if err != nil {
+		log.Fatalf("Error connecting to the node\n%v", err)
+	}
+	return blockchain
+}
+
 func LoadPostgres(database config.Database, node core.Node) repositories.Postgres {
 	repository, err := repositories.NewPostgres(database, node)
 	if err != nil {
@@ -67,3 +78,22 @@ func RequestedBlockNumber(blockNumber *int64) *big.Int {
 	}
 	return _blockNumber
 }
+
+func ParseArguments(arguments string) []string {
+	if arguments == "" {
+		return nil
+	}
+	var parsedArguments []string
+	for _, argument := range strings.Split(arguments, ",") {
+		parsedArguments = append(parsedArguments, strings.TrimSpace(argument))
+	}
+	return parsedArguments
+}
+
+const backfillProgressInterval = 1000
+
+func LogBackfillProgress(progress history.BackfillProgress) {
+	if progress.Populated%backfillProgressInterval == 0 || progress.Populated == progress.Total {
+		log.Printf("Populated %d of %d missing blocks, up to block %d\n", progress.Populated, progress.Total, progress.BlockNumber)
+	}
+}
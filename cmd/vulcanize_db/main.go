@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 
 	"time"
 
@@ -27,50 +29,78 @@ const (
 	pollingInterval = 10 * time.Second
 )
 
-func createListener(blockchain *geth.GethBlockchain, repository repositories.Postgres) blockchain_listener.BlockchainListener {
-	listener := blockchain_listener.NewBlockchainListener(
+func createListener(ctx context.Context, blockchain *geth.GethBlockchain, repository repositories.Postgres, stateInterval int64) blockchain_listener.BlockchainListener {
+	listener, err := blockchain_listener.NewBlockchainListener(
+		ctx,
 		blockchain,
 		[]core.BlockchainObserver{
 			observers.BlockchainLoggingObserver{},
-			observers.NewBlockchainDbObserver(repository),
+			observers.NewBlockchainDbObserver(blockchain, repository),
+			observers.NewContractStateObserver(blockchain, repository, stateInterval),
 		},
 	)
+	if err != nil {
+		log.Fatalf("Error subscribing to new blocks\n%v", err)
+	}
 	return listener
 }
 
-func validateBlocks(blockchain *geth.GethBlockchain, repository repositories.Postgres, windowSize int, windowTemplate *template.Template) {
-	window := history.UpdateBlocksWindow(blockchain, repository, windowSize)
-	repository.SetBlocksStatus(blockchain.LastBlock().Int64())
+func validateBlocks(ctx context.Context, blockchain *geth.GethBlockchain, repository repositories.Postgres, windowSize int, windowTemplate *template.Template) {
+	window := history.UpdateBlocksWindow(ctx, blockchain, repository, windowSize)
+	replaceOrphanedBlocksBelowWindow(ctx, blockchain, repository, window)
+	lastBlock, err := blockchain.LastBlock(ctx)
+	if err != nil {
+		log.Printf("Error retrieving the last block\n%v", err)
+	} else {
+		repository.SetBlocksStatus(lastBlock.Int64())
+	}
 	windowTemplate.Execute(os.Stdout, window)
 }
 
+func replaceOrphanedBlocksBelowWindow(ctx context.Context, blockchain *geth.GethBlockchain, repository repositories.Postgres, window history.Window) {
+	lowestBlock, err := repository.FindBlockByNumber(int64(window.LowerBound))
+	if err != nil {
+		return
+	}
+	reorg, err := history.ReplaceOrphanedAncestors(ctx, blockchain, repository, lowestBlock)
+	if err != nil {
+		log.Printf("Error replacing orphaned blocks below the validation window\n%v", err)
+	}
+	if reorg.Depth() > 0 {
+		log.Printf("Replaced %d orphaned blocks below the validation window\n", reorg.Depth())
+	}
+}
+
 func main() {
 	parsedWindowTemplate := template.Must(template.New("window").Parse(windowTemplate))
-	ticker := time.NewTicker(pollingInterval)
-	defer ticker.Stop()
 
 	environment := flag.String("environment", "", "Environment name")
+	stateInterval := flag.Int64("state-interval", 1, "Number of blocks between recorded contract states")
+	concurrency := flag.Int("backfill-concurrency", 4, "Number of missing blocks to fetch at once")
+	rateLimit := flag.Int("rate-limit", 0, "Maximum calls per second to the node, counting each call in a batch, 0 for no limit")
 	flag.Parse()
 	config := cmd.LoadConfig(*environment)
-	blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+	blockchain := cmd.LoadBlockchain(config.Client)
+	blockchain.LimitRate(*rateLimit)
 	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
-	listner := createListener(blockchain, repository)
-	go listner.Start()
-	defer listner.Stop()
+	lifecycle := cmd.NewLifecycle()
+	listener := createListener(lifecycle.Context(), blockchain, repository, *stateInterval)
+	lifecycle.Go("listening for new blocks", listener.Start)
 
-	missingBlocksPopulated := make(chan int)
-	go func() {
-		missingBlocksPopulated <- history.PopulateMissingBlocks(blockchain, repository, 0)
-	}()
+	backfiller := history.NewBackfiller(blockchain, repository, *concurrency)
+	backfiller.Progress = cmd.LogBackfillProgress
+	lifecycle.Go("populating missing blocks", func(ctx context.Context) error {
+		return cmd.Every(ctx, pollingInterval, func() {
+			backfiller.PopulateMissingBlocks(ctx, 0)
+		})
+	})
+	lifecycle.Go("validating blocks", func(ctx context.Context) error {
+		return cmd.Every(ctx, pollingInterval, func() {
+			validateBlocks(ctx, blockchain, repository, windowSize, parsedWindowTemplate)
+		})
+	})
 
-	for range ticker.C {
-		validateBlocks(blockchain, repository, windowSize, parsedWindowTemplate)
-		select {
-		case <-missingBlocksPopulated:
-			go func() {
-				missingBlocksPopulated <- history.PopulateMissingBlocks(blockchain, repository, 0)
-			}()
-		default:
-		}
-	}
+	err := lifecycle.Wait()
+	listener.Stop()
+	cmd.Exit(err)
 }
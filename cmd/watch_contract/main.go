@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 
 	"github.com/vulcanize/vulcanizedb/cmd"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
-	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 )
 
 func main() {
@@ -16,11 +18,26 @@ func main() {
 
 	contractAbiString := cmd.GetAbi(*abiFilepath, *contractHash)
 	config := cmd.LoadConfig(*environment)
-	blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+	blockchain := cmd.LoadBlockchain(config.Client)
 	repository := cmd.LoadPostgres(config.Database, blockchain.Node())
+	deploymentBlock, err := blockchain.FindDeploymentBlock(context.Background(), repository, *contractHash)
+	if err != nil {
+		log.Printf("Error finding the deployment block, backfills will start at block 0\n%v", err)
+	} else {
+		log.Printf("Contract %s was deployed in block %d\n", *contractHash, deploymentBlock)
+	}
 	watchedContract := core.Contract{
-		Abi:  contractAbiString,
-		Hash: *contractHash,
+		Abi:             contractAbiString,
+		Hash:            *contractHash,
+		DeploymentBlock: deploymentBlock,
 	}
 	repository.CreateContract(watchedContract)
+	contract, err := repository.FindContract(*contractHash)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = history.DecodeContractCalls(repository, contract.Transactions)
+	if err != nil {
+		log.Fatalln(err)
+	}
 }
@@ -0,0 +1 @@
+DROP TABLE receipts;
\ No newline at end of file
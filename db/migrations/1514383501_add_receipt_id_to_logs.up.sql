@@ -0,0 +1,6 @@
+ALTER TABLE logs
+  ADD COLUMN receipt_id INTEGER,
+  ADD CONSTRAINT receipts_fk
+FOREIGN KEY (receipt_id)
+REFERENCES receipts (id)
+ON DELETE CASCADE;
@@ -0,0 +1 @@
+DROP INDEX receipts_tx_hash_index;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX receipts_tx_hash_index ON receipts (tx_hash);
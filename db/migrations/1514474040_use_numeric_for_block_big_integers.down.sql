@@ -0,0 +1,14 @@
+-- Difficulties beyond the BIGINT range cannot be reversed without losing
+-- them, so the migration stops instead of truncating.
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM blocks WHERE block_difficulty > 9223372036854775807) THEN
+    RAISE EXCEPTION 'blocks has difficulties beyond the BIGINT range, which this migration cannot reverse';
+  END IF;
+END
+$$;
+
+ALTER TABLE blocks
+  ALTER COLUMN block_difficulty TYPE BIGINT,
+  ALTER COLUMN block_gaslimit TYPE DOUBLE PRECISION,
+  ALTER COLUMN block_gasused TYPE DOUBLE PRECISION;
@@ -0,0 +1,4 @@
+ALTER TABLE blocks
+  ALTER COLUMN block_difficulty TYPE NUMERIC,
+  ALTER COLUMN block_gaslimit TYPE NUMERIC,
+  ALTER COLUMN block_gasused TYPE NUMERIC;
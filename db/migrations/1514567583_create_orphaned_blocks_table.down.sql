@@ -0,0 +1 @@
+DROP TABLE orphaned_blocks;
\ No newline at end of file
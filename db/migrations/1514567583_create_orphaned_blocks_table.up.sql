@@ -0,0 +1,13 @@
+CREATE TABLE orphaned_blocks (
+  id               SERIAL PRIMARY KEY,
+  node_id          INTEGER NOT NULL,
+  block_number     BIGINT,
+  block_hash       VARCHAR(66),
+  block_parenthash VARCHAR(66),
+  orphaned_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT orphaned_blocks_node_fk FOREIGN KEY (node_id)
+  REFERENCES nodes (id)
+  ON DELETE CASCADE
+);
+
+CREATE INDEX orphaned_blocks_block_number_index ON orphaned_blocks (block_number);
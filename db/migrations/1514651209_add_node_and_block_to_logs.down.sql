@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP INDEX logs_block_id_index;
+
+ALTER TABLE logs
+  DROP CONSTRAINT log_uc,
+  DROP COLUMN node_id,
+  DROP COLUMN block_id;
+
+DELETE FROM logs a
+USING logs b
+WHERE a.id > b.id AND a.block_number = b.block_number AND a.index = b.index;
+
+ALTER TABLE logs
+  ADD CONSTRAINT log_uc UNIQUE (block_number, index);
+
+COMMIT;
@@ -0,0 +1,41 @@
+BEGIN;
+
+ALTER TABLE logs
+  ADD COLUMN node_id INTEGER,
+  ADD COLUMN block_id INTEGER,
+  ADD CONSTRAINT logs_node_fk
+FOREIGN KEY (node_id)
+REFERENCES nodes (id)
+ON DELETE CASCADE,
+  ADD CONSTRAINT logs_block_fk
+FOREIGN KEY (block_id)
+REFERENCES blocks (id)
+ON DELETE CASCADE;
+
+-- logs were not tied to a node, so they can only be attributed to one when
+-- a single node is known or a single node has synced their block; the rest
+-- are kept without a node
+UPDATE logs
+SET node_id = (SELECT id FROM nodes)
+WHERE (SELECT count(*) FROM nodes) = 1;
+
+UPDATE logs
+SET node_id = synced.node_id
+FROM (SELECT block_number, min(node_id) AS node_id
+      FROM blocks
+      GROUP BY block_number
+      HAVING count(DISTINCT node_id) = 1) synced
+WHERE logs.node_id IS NULL AND synced.block_number = logs.block_number;
+
+UPDATE logs
+SET block_id = blocks.id
+FROM blocks
+WHERE blocks.node_id = logs.node_id AND blocks.block_number = logs.block_number;
+
+ALTER TABLE logs
+  DROP CONSTRAINT log_uc,
+  ADD CONSTRAINT log_uc UNIQUE (node_id, block_number, index);
+
+CREATE INDEX logs_block_id_index ON logs (block_id);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE decoded_events;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE decoded_events (
+  id        SERIAL PRIMARY KEY,
+  log_id    INTEGER NOT NULL,
+  name      VARCHAR(100),
+  arguments JSONB,
+  CONSTRAINT decoded_events_log_uc UNIQUE (log_id),
+  CONSTRAINT decoded_events_log_fk FOREIGN KEY (log_id)
+  REFERENCES logs (id)
+  ON DELETE CASCADE
+);
+
+CREATE INDEX decoded_events_name_index ON decoded_events (name);
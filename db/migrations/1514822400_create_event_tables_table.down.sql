@@ -0,0 +1 @@
+DROP TABLE event_tables;
\ No newline at end of file
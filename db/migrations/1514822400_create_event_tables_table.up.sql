@@ -0,0 +1,11 @@
+CREATE TABLE event_tables (
+  id            SERIAL PRIMARY KEY,
+  name          VARCHAR(63) NOT NULL,
+  contract_hash VARCHAR(66),
+  event_name    VARCHAR(100),
+  columns       JSONB,
+  last_decoded_event_id INTEGER NOT NULL DEFAULT 0,
+  CONSTRAINT event_tables_name_uc UNIQUE (name)
+);
+
+CREATE INDEX event_tables_contract_hash_index ON event_tables (contract_hash);
@@ -0,0 +1,2 @@
+ALTER TABLE transactions
+  DROP COLUMN input_data;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions
+  ADD COLUMN input_data BYTEA;
@@ -0,0 +1 @@
+DROP TABLE decoded_calls;
\ No newline at end of file
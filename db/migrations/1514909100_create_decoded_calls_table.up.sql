@@ -0,0 +1,12 @@
+CREATE TABLE decoded_calls (
+  id             SERIAL PRIMARY KEY,
+  transaction_id INTEGER NOT NULL,
+  method_name    VARCHAR(100),
+  arguments      JSONB,
+  CONSTRAINT decoded_calls_transaction_uc UNIQUE (transaction_id),
+  CONSTRAINT decoded_calls_transaction_fk FOREIGN KEY (transaction_id)
+  REFERENCES transactions (id)
+  ON DELETE CASCADE
+);
+
+CREATE INDEX decoded_calls_method_name_index ON decoded_calls (method_name);
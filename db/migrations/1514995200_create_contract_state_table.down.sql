@@ -0,0 +1 @@
+DROP TABLE contract_state;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE contract_state (
+  id             SERIAL PRIMARY KEY,
+  node_id        INTEGER NOT NULL,
+  block_id       INTEGER,
+  contract_hash  VARCHAR(66) NOT NULL,
+  block_number   BIGINT NOT NULL,
+  attribute_name VARCHAR(100) NOT NULL,
+  value          TEXT,
+  CONSTRAINT contract_state_uc UNIQUE (node_id, contract_hash, block_number, attribute_name),
+  CONSTRAINT contract_state_node_fk FOREIGN KEY (node_id)
+  REFERENCES nodes (id)
+  ON DELETE CASCADE,
+  CONSTRAINT contract_state_block_fk FOREIGN KEY (block_id)
+  REFERENCES blocks (id)
+  ON DELETE CASCADE
+);
+
+CREATE INDEX contract_state_block_id_index ON contract_state (block_id);
@@ -0,0 +1,2 @@
+DROP TABLE failed_log_ranges;
+DROP TABLE log_checkpoints;
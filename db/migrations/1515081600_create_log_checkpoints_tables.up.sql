@@ -0,0 +1,24 @@
+CREATE TABLE log_checkpoints (
+  id            SERIAL PRIMARY KEY,
+  node_id       INTEGER NOT NULL,
+  contract_hash VARCHAR(66) NOT NULL,
+  topics_key    TEXT NOT NULL DEFAULT '',
+  block_number  BIGINT NOT NULL,
+  CONSTRAINT log_checkpoints_uc UNIQUE (node_id, contract_hash, topics_key),
+  CONSTRAINT log_checkpoints_node_fk FOREIGN KEY (node_id)
+  REFERENCES nodes (id)
+  ON DELETE CASCADE
+);
+
+CREATE TABLE failed_log_ranges (
+  id                    SERIAL PRIMARY KEY,
+  node_id               INTEGER NOT NULL,
+  contract_hash         VARCHAR(66) NOT NULL,
+  topics_key            TEXT NOT NULL DEFAULT '',
+  starting_block_number BIGINT NOT NULL,
+  ending_block_number   BIGINT NOT NULL,
+  CONSTRAINT failed_log_ranges_uc UNIQUE (node_id, contract_hash, topics_key, starting_block_number, ending_block_number),
+  CONSTRAINT failed_log_ranges_node_fk FOREIGN KEY (node_id)
+  REFERENCES nodes (id)
+  ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE watched_contracts
+  DROP COLUMN deployment_block;
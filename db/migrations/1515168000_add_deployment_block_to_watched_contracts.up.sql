@@ -0,0 +1,2 @@
+ALTER TABLE watched_contracts
+  ADD COLUMN deployment_block BIGINT;
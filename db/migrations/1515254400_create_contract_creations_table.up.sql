@@ -0,0 +1,14 @@
+CREATE TABLE contract_creations (
+  id               SERIAL PRIMARY KEY,
+  transaction_id   INTEGER NOT NULL,
+  creator_address  VARCHAR(66),
+  contract_address VARCHAR(66),
+  code_hash        VARCHAR(66),
+  CONSTRAINT contract_creations_transaction_uc UNIQUE (transaction_id),
+  CONSTRAINT contract_creations_transaction_fk FOREIGN KEY (transaction_id)
+  REFERENCES transactions (id)
+  ON DELETE CASCADE
+);
+
+CREATE INDEX contract_creations_creator_address_index ON contract_creations (creator_address);
+CREATE INDEX contract_creations_contract_address_index ON contract_creations (contract_address);
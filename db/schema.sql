@@ -40,11 +40,11 @@ SET default_with_oids = false;
 
 CREATE TABLE blocks (
     block_number bigint,
-    block_gaslimit double precision,
-    block_gasused double precision,
+    block_gaslimit numeric,
+    block_gasused numeric,
     block_time double precision,
     id integer NOT NULL,
-    block_difficulty bigint,
+    block_difficulty numeric,
     block_hash character varying(66),
     block_nonce character varying(20),
     block_parenthash character varying(66),
@@ -74,6 +74,232 @@ CREATE SEQUENCE blocks_id_seq
 ALTER SEQUENCE blocks_id_seq OWNED BY blocks.id;
 
 
+--
+-- Name: contract_creations; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE contract_creations (
+    id integer NOT NULL,
+    transaction_id integer NOT NULL,
+    creator_address character varying(66),
+    contract_address character varying(66),
+    code_hash character varying(66)
+);
+
+
+--
+-- Name: contract_creations_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE contract_creations_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: contract_creations_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE contract_creations_id_seq OWNED BY contract_creations.id;
+
+
+--
+-- Name: contract_state; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE contract_state (
+    id integer NOT NULL,
+    node_id integer NOT NULL,
+    block_id integer,
+    contract_hash character varying(66) NOT NULL,
+    block_number bigint NOT NULL,
+    attribute_name character varying(100) NOT NULL,
+    value text
+);
+
+
+--
+-- Name: contract_state_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE contract_state_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: contract_state_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE contract_state_id_seq OWNED BY contract_state.id;
+
+
+--
+-- Name: decoded_calls; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE decoded_calls (
+    id integer NOT NULL,
+    transaction_id integer NOT NULL,
+    method_name character varying(100),
+    arguments jsonb
+);
+
+
+--
+-- Name: decoded_calls_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE decoded_calls_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: decoded_calls_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE decoded_calls_id_seq OWNED BY decoded_calls.id;
+
+
+--
+-- Name: decoded_events; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE decoded_events (
+    id integer NOT NULL,
+    log_id integer NOT NULL,
+    name character varying(100),
+    arguments jsonb
+);
+
+
+--
+-- Name: decoded_events_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE decoded_events_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: decoded_events_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE decoded_events_id_seq OWNED BY decoded_events.id;
+
+
+--
+-- Name: event_tables; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE event_tables (
+    id integer NOT NULL,
+    name character varying(63) NOT NULL,
+    contract_hash character varying(66),
+    event_name character varying(100),
+    columns jsonb,
+    last_decoded_event_id integer DEFAULT 0 NOT NULL
+);
+
+
+--
+-- Name: event_tables_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE event_tables_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: event_tables_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE event_tables_id_seq OWNED BY event_tables.id;
+
+
+--
+-- Name: failed_log_ranges; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE failed_log_ranges (
+    id integer NOT NULL,
+    node_id integer NOT NULL,
+    contract_hash character varying(66) NOT NULL,
+    topics_key text DEFAULT ''::text NOT NULL,
+    starting_block_number bigint NOT NULL,
+    ending_block_number bigint NOT NULL
+);
+
+
+--
+-- Name: failed_log_ranges_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE failed_log_ranges_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: failed_log_ranges_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE failed_log_ranges_id_seq OWNED BY failed_log_ranges.id;
+
+
+--
+-- Name: log_checkpoints; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE log_checkpoints (
+    id integer NOT NULL,
+    node_id integer NOT NULL,
+    contract_hash character varying(66) NOT NULL,
+    topics_key text DEFAULT ''::text NOT NULL,
+    block_number bigint NOT NULL
+);
+
+
+--
+-- Name: log_checkpoints_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE log_checkpoints_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: log_checkpoints_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE log_checkpoints_id_seq OWNED BY log_checkpoints.id;
+
+
 --
 -- Name: logs; Type: TABLE; Schema: public; Owner: -
 --
@@ -88,7 +314,10 @@ CREATE TABLE logs (
     topic1 character varying(66),
     topic2 character varying(66),
     topic3 character varying(66),
-    data text
+    data text,
+    receipt_id integer,
+    node_id integer,
+    block_id integer
 );
 
 
@@ -141,6 +370,75 @@ CREATE SEQUENCE nodes_id_seq
 ALTER SEQUENCE nodes_id_seq OWNED BY nodes.id;
 
 
+--
+-- Name: orphaned_blocks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE orphaned_blocks (
+    id integer NOT NULL,
+    node_id integer NOT NULL,
+    block_number bigint,
+    block_hash character varying(66),
+    block_parenthash character varying(66),
+    orphaned_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: orphaned_blocks_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE orphaned_blocks_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: orphaned_blocks_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE orphaned_blocks_id_seq OWNED BY orphaned_blocks.id;
+
+
+--
+-- Name: receipts; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE receipts (
+    id integer NOT NULL,
+    transaction_id integer NOT NULL,
+    contract_address character varying(42),
+    cumulative_gas_used numeric,
+    gas_used numeric,
+    state_root character varying(66),
+    status integer,
+    tx_hash character varying(66),
+    bloom text
+);
+
+
+--
+-- Name: receipts_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE receipts_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: receipts_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE receipts_id_seq OWNED BY receipts.id;
+
+
 --
 -- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
 --
@@ -164,7 +462,8 @@ CREATE TABLE transactions (
     tx_gasprice numeric,
     tx_value numeric,
     block_id integer NOT NULL,
-    tx_from character varying(66)
+    tx_from character varying(66),
+    input_data bytea
 );
 
 
@@ -194,7 +493,8 @@ ALTER SEQUENCE transactions_id_seq OWNED BY transactions.id;
 CREATE TABLE watched_contracts (
     contract_id integer NOT NULL,
     contract_hash character varying(66),
-    contract_abi json
+    contract_abi json,
+    deployment_block bigint
 );
 
 
@@ -224,6 +524,55 @@ ALTER SEQUENCE watched_contracts_contract_id_seq OWNED BY watched_contracts.cont
 ALTER TABLE ONLY blocks ALTER COLUMN id SET DEFAULT nextval('blocks_id_seq'::regclass);
 
 
+--
+-- Name: contract_creations id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_creations ALTER COLUMN id SET DEFAULT nextval('contract_creations_id_seq'::regclass);
+
+
+--
+-- Name: contract_state id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_state ALTER COLUMN id SET DEFAULT nextval('contract_state_id_seq'::regclass);
+
+
+--
+-- Name: decoded_calls id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_calls ALTER COLUMN id SET DEFAULT nextval('decoded_calls_id_seq'::regclass);
+
+
+--
+-- Name: decoded_events id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_events ALTER COLUMN id SET DEFAULT nextval('decoded_events_id_seq'::regclass);
+
+
+--
+-- Name: event_tables id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY event_tables ALTER COLUMN id SET DEFAULT nextval('event_tables_id_seq'::regclass);
+
+
+--
+-- Name: failed_log_ranges id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY failed_log_ranges ALTER COLUMN id SET DEFAULT nextval('failed_log_ranges_id_seq'::regclass);
+
+
+--
+-- Name: log_checkpoints id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY log_checkpoints ALTER COLUMN id SET DEFAULT nextval('log_checkpoints_id_seq'::regclass);
+
+
 --
 -- Name: logs id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -238,6 +587,20 @@ ALTER TABLE ONLY logs ALTER COLUMN id SET DEFAULT nextval('logs_id_seq'::regclas
 ALTER TABLE ONLY nodes ALTER COLUMN id SET DEFAULT nextval('nodes_id_seq'::regclass);
 
 
+--
+-- Name: orphaned_blocks id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY orphaned_blocks ALTER COLUMN id SET DEFAULT nextval('orphaned_blocks_id_seq'::regclass);
+
+
+--
+-- Name: receipts id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY receipts ALTER COLUMN id SET DEFAULT nextval('receipts_id_seq'::regclass);
+
+
 --
 -- Name: transactions id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -260,6 +623,22 @@ ALTER TABLE ONLY blocks
     ADD CONSTRAINT blocks_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: contract_creations contract_creations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_creations
+    ADD CONSTRAINT contract_creations_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: contract_creations contract_creations_transaction_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_creations
+    ADD CONSTRAINT contract_creations_transaction_uc UNIQUE (transaction_id);
+
+
 --
 -- Name: watched_contracts contract_hash_uc; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -268,12 +647,108 @@ ALTER TABLE ONLY watched_contracts
     ADD CONSTRAINT contract_hash_uc UNIQUE (contract_hash);
 
 
+--
+-- Name: contract_state contract_state_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_state
+    ADD CONSTRAINT contract_state_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: contract_state contract_state_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_state
+    ADD CONSTRAINT contract_state_uc UNIQUE (node_id, contract_hash, block_number, attribute_name);
+
+
+--
+-- Name: decoded_calls decoded_calls_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_calls
+    ADD CONSTRAINT decoded_calls_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: decoded_calls decoded_calls_transaction_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_calls
+    ADD CONSTRAINT decoded_calls_transaction_uc UNIQUE (transaction_id);
+
+
+--
+-- Name: decoded_events decoded_events_log_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_events
+    ADD CONSTRAINT decoded_events_log_uc UNIQUE (log_id);
+
+
+--
+-- Name: decoded_events decoded_events_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_events
+    ADD CONSTRAINT decoded_events_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: event_tables event_tables_name_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY event_tables
+    ADD CONSTRAINT event_tables_name_uc UNIQUE (name);
+
+
+--
+-- Name: event_tables event_tables_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY event_tables
+    ADD CONSTRAINT event_tables_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: failed_log_ranges failed_log_ranges_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY failed_log_ranges
+    ADD CONSTRAINT failed_log_ranges_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: failed_log_ranges failed_log_ranges_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY failed_log_ranges
+    ADD CONSTRAINT failed_log_ranges_uc UNIQUE (node_id, contract_hash, topics_key, starting_block_number, ending_block_number);
+
+
+--
+-- Name: log_checkpoints log_checkpoints_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY log_checkpoints
+    ADD CONSTRAINT log_checkpoints_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: log_checkpoints log_checkpoints_uc; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY log_checkpoints
+    ADD CONSTRAINT log_checkpoints_uc UNIQUE (node_id, contract_hash, topics_key);
+
+
 --
 -- Name: logs log_uc; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
 ALTER TABLE ONLY logs
-    ADD CONSTRAINT log_uc UNIQUE (block_number, index);
+    ADD CONSTRAINT log_uc UNIQUE (node_id, block_number, index);
 
 
 --
@@ -308,6 +783,22 @@ ALTER TABLE ONLY nodes
     ADD CONSTRAINT nodes_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: orphaned_blocks orphaned_blocks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY orphaned_blocks
+    ADD CONSTRAINT orphaned_blocks_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: receipts receipts_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY receipts
+    ADD CONSTRAINT receipts_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -346,6 +837,55 @@ CREATE INDEX block_id_index ON transactions USING btree (block_id);
 CREATE INDEX block_number_index ON blocks USING btree (block_number);
 
 
+--
+-- Name: contract_creations_contract_address_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX contract_creations_contract_address_index ON contract_creations USING btree (contract_address);
+
+
+--
+-- Name: contract_creations_creator_address_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX contract_creations_creator_address_index ON contract_creations USING btree (creator_address);
+
+
+--
+-- Name: contract_state_block_id_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX contract_state_block_id_index ON contract_state USING btree (block_id);
+
+
+--
+-- Name: decoded_calls_method_name_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX decoded_calls_method_name_index ON decoded_calls USING btree (method_name);
+
+
+--
+-- Name: decoded_events_name_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX decoded_events_name_index ON decoded_events USING btree (name);
+
+
+--
+-- Name: event_tables_contract_hash_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX event_tables_contract_hash_index ON event_tables USING btree (contract_hash);
+
+
+--
+-- Name: logs_block_id_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX logs_block_id_index ON logs USING btree (block_id);
+
+
 --
 -- Name: node_id_index; Type: INDEX; Schema: public; Owner: -
 --
@@ -353,6 +893,20 @@ CREATE INDEX block_number_index ON blocks USING btree (block_number);
 CREATE INDEX node_id_index ON blocks USING btree (node_id);
 
 
+--
+-- Name: orphaned_blocks_block_number_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX orphaned_blocks_block_number_index ON orphaned_blocks USING btree (block_number);
+
+
+--
+-- Name: receipts_tx_hash_index; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX receipts_tx_hash_index ON receipts USING btree (tx_hash);
+
+
 --
 -- Name: tx_from_index; Type: INDEX; Schema: public; Owner: -
 --
@@ -375,6 +929,78 @@ ALTER TABLE ONLY transactions
     ADD CONSTRAINT blocks_fk FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
 
 
+--
+-- Name: contract_creations contract_creations_transaction_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_creations
+    ADD CONSTRAINT contract_creations_transaction_fk FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: contract_state contract_state_block_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_state
+    ADD CONSTRAINT contract_state_block_fk FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: contract_state contract_state_node_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY contract_state
+    ADD CONSTRAINT contract_state_node_fk FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: decoded_calls decoded_calls_transaction_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_calls
+    ADD CONSTRAINT decoded_calls_transaction_fk FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: decoded_events decoded_events_log_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY decoded_events
+    ADD CONSTRAINT decoded_events_log_fk FOREIGN KEY (log_id) REFERENCES logs(id) ON DELETE CASCADE;
+
+
+--
+-- Name: failed_log_ranges failed_log_ranges_node_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY failed_log_ranges
+    ADD CONSTRAINT failed_log_ranges_node_fk FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: log_checkpoints log_checkpoints_node_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY log_checkpoints
+    ADD CONSTRAINT log_checkpoints_node_fk FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: logs logs_block_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY logs
+    ADD CONSTRAINT logs_block_fk FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: logs logs_node_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY logs
+    ADD CONSTRAINT logs_node_fk FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE;
+
+
 --
 -- Name: blocks node_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -383,6 +1009,30 @@ ALTER TABLE ONLY blocks
     ADD CONSTRAINT node_fk FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE;
 
 
+--
+-- Name: orphaned_blocks orphaned_blocks_node_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY orphaned_blocks
+    ADD CONSTRAINT orphaned_blocks_node_fk FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: logs receipts_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY logs
+    ADD CONSTRAINT receipts_fk FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE;
+
+
+--
+-- Name: receipts transaction_fk; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY receipts
+    ADD CONSTRAINT transaction_fk FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE;
+
+
 --
 -- PostgreSQL database dump complete
 --
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"math/big"
 
 	"log"
@@ -21,10 +22,10 @@ var _ = Describe("Reading contracts", func() {
 			if err != nil {
 				log.Fatalln(err)
 			}
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			contractAttributes, err := blockchain.GetAttributes(contract)
+			contractAttributes, err := blockchain.GetAttributes(context.Background(), contract)
 
 			Expect(err).To(BeNil())
 			Expect(len(contractAttributes)).NotTo(Equal(0))
@@ -35,10 +36,10 @@ var _ = Describe("Reading contracts", func() {
 
 		It("does not return an attribute that takes an input", func() {
 			config, err := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			contractAttributes, err := blockchain.GetAttributes(contract)
+			contractAttributes, err := blockchain.GetAttributes(context.Background(), contract)
 
 			Expect(err).To(BeNil())
 			attribute := testing.FindAttribute(contractAttributes, "balanceOf")
@@ -47,10 +48,10 @@ var _ = Describe("Reading contracts", func() {
 
 		It("does not return an attribute that is not constant", func() {
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			contractAttributes, err := blockchain.GetAttributes(contract)
+			contractAttributes, err := blockchain.GetAttributes(context.Background(), contract)
 
 			Expect(err).To(BeNil())
 			attribute := testing.FindAttribute(contractAttributes, "unpause")
@@ -61,10 +62,10 @@ var _ = Describe("Reading contracts", func() {
 	Describe("Getting a contract attribute", func() {
 		It("returns the correct attribute for a real contract", func() {
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 
 			contract := testing.SampleContract()
-			name, err := blockchain.GetAttribute(contract, "name", nil)
+			name, err := blockchain.GetAttribute(context.Background(), contract, "name", nil)
 
 			Expect(err).To(BeNil())
 			Expect(name).To(Equal("OMGToken"))
@@ -72,10 +73,10 @@ var _ = Describe("Reading contracts", func() {
 
 		It("returns the correct attribute for a real contract", func() {
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			name, err := blockchain.GetAttribute(contract, "name", nil)
+			name, err := blockchain.GetAttribute(context.Background(), contract, "name", nil)
 
 			Expect(err).To(BeNil())
 			Expect(name).To(Equal("OMGToken"))
@@ -83,10 +84,10 @@ var _ = Describe("Reading contracts", func() {
 
 		It("returns the correct attribute for a real contract at a specific block height", func() {
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			name, err := blockchain.GetAttribute(contract, "name", big.NewInt(4701536))
+			name, err := blockchain.GetAttribute(context.Background(), contract, "name", big.NewInt(4701536))
 
 			Expect(name).To(Equal("OMGToken"))
 			Expect(err).To(BeNil())
@@ -94,15 +95,37 @@ var _ = Describe("Reading contracts", func() {
 
 		It("returns an error when asking for an attribute that does not exist", func() {
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			name, err := blockchain.GetAttribute(contract, "missing_attribute", nil)
+			name, err := blockchain.GetAttribute(context.Background(), contract, "missing_attribute", nil)
 
 			Expect(err).To(Equal(geth.ErrInvalidStateAttribute))
 			Expect(name).To(BeNil())
 		})
 
+		It("returns the result of an attribute that takes arguments", func() {
+			config, _ := cfg.NewConfig("infura")
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
+			contract := testing.SampleContract()
+
+			balance, err := blockchain.GetAttributeWithArguments(context.Background(), contract, "balanceOf", []string{"0xfbb1b73c4f0bda4f67dca266ce6ef42f520fbb98"}, big.NewInt(4703824))
+
+			Expect(err).To(BeNil())
+			Expect(balance).To(BeAssignableToTypeOf(&big.Int{}))
+		})
+
+		It("returns an error when an attribute is given the wrong arguments", func() {
+			config, _ := cfg.NewConfig("infura")
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
+			contract := testing.SampleContract()
+
+			balance, err := blockchain.GetAttributeWithArguments(context.Background(), contract, "balanceOf", []string{"not an address"}, nil)
+
+			Expect(err).To(Equal(geth.ErrInvalidArgument))
+			Expect(balance).To(BeNil())
+		})
+
 		It("retrieves the event log for a specific block and contract", func() {
 			expectedLogZero := core.Log{
 				BlockNumber: 4703824,
@@ -116,10 +139,10 @@ var _ = Describe("Reading contracts", func() {
 				Index: 19,
 				Data:  "0x0000000000000000000000000000000000000000000000000c7d713b49da0000"}
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 			contract := testing.SampleContract()
 
-			logs, err := blockchain.GetLogs(contract, big.NewInt(4703824), nil)
+			logs, err := blockchain.GetLogs(context.Background(), contract, big.NewInt(4703824), nil)
 
 			Expect(err).To(BeNil())
 			Expect(len(logs)).To(Equal(3))
@@ -129,9 +152,9 @@ var _ = Describe("Reading contracts", func() {
 
 		It("returns and empty log array when no events for a given block / contract combo", func() {
 			config, _ := cfg.NewConfig("infura")
-			blockchain := geth.NewGethBlockchain(config.Client.IPCPath)
+			blockchain, _ := geth.NewGethBlockchain(config.Client)
 
-			logs, err := blockchain.GetLogs(core.Contract{Hash: "x123"}, big.NewInt(4703824), nil)
+			logs, err := blockchain.GetLogs(context.Background(), core.Contract{Hash: "x123"}, big.NewInt(4703824), nil)
 
 			Expect(err).To(BeNil())
 			Expect(len(logs)).To(Equal(0))
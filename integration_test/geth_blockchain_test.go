@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"context"
 	"io/ioutil"
 	"log"
 
@@ -26,9 +27,9 @@ var _ = Describe("Reading from the Geth blockchain", func() {
 	BeforeEach(func() {
 		observer = fakes.NewFakeBlockchainObserver()
 		cfg, _ := config.NewConfig("private")
-		blockchain = geth.NewGethBlockchain(cfg.Client.IPCPath)
+		blockchain, _ = geth.NewGethBlockchain(cfg.Client)
 		observers := []core.BlockchainObserver{observer}
-		listener = blockchain_listener.NewBlockchainListener(blockchain, observers)
+		listener, _ = blockchain_listener.NewBlockchainListener(context.Background(), blockchain, observers)
 	})
 
 	AfterEach(func() {
@@ -36,7 +37,7 @@ var _ = Describe("Reading from the Geth blockchain", func() {
 	})
 
 	It("reads two blocks", func(done Done) {
-		go listener.Start()
+		go listener.Start(context.Background())
 
 		<-observer.WasNotified
 		firstBlock := observer.LastBlock()
@@ -52,9 +53,9 @@ var _ = Describe("Reading from the Geth blockchain", func() {
 	}, 15)
 
 	It("retrieves the genesis block and first block", func(done Done) {
-		genesisBlock := blockchain.GetBlockByNumber(int64(0))
-		firstBlock := blockchain.GetBlockByNumber(int64(1))
-		lastBlockNumber := blockchain.LastBlock()
+		genesisBlock, _ := blockchain.GetBlockByNumber(context.Background(), int64(0))
+		firstBlock, _ := blockchain.GetBlockByNumber(context.Background(), int64(1))
+		lastBlockNumber, _ := blockchain.LastBlock(context.Background())
 
 		Expect(genesisBlock.Number).To(Equal(int64(0)))
 		Expect(firstBlock.Number).To(Equal(int64(1)))
@@ -62,6 +63,16 @@ var _ = Describe("Reading from the Geth blockchain", func() {
 		close(done)
 	}, 15)
 
+	It("retrieves a range of blocks in a batch", func(done Done) {
+		blocks, err := blockchain.GetBlocksByNumber(context.Background(), []int64{0, 1})
+		genesisBlock, _ := blockchain.GetBlockByNumber(context.Background(), 0)
+		firstBlock, _ := blockchain.GetBlockByNumber(context.Background(), 1)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blocks).To(Equal([]core.Block{genesisBlock, firstBlock}))
+		close(done)
+	}, 15)
+
 	It("retrieves the node info", func(done Done) {
 		node := blockchain.Node()
 		devNetworkGenesisBlock := "0xe5be92145a301820111f91866566e3e99ee344d155569e4556a39bc71238f3bc"
@@ -0,0 +1,527 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+var (
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrMissingBlockLocator = errors.New("block requires a number or a hash")
+)
+
+// NewGraphQLHandler serves the GraphQL schema over the repository. Queries
+// are POSTed as JSON: {"query": "...", "variables": {...}}.
+func NewGraphQLHandler(repository repositories.Repository) http.Handler {
+	schema := graphql.MustParseSchema(graphQLSchema, &queryResolver{repository: repository})
+	return &relay.Handler{Schema: schema}
+}
+
+type queryResolver struct {
+	repository repositories.Repository
+}
+
+func (resolver *queryResolver) Block(args struct {
+	Number *int32
+	Hash   *string
+}) (*blockResolver, error) {
+	var block core.Block
+	var err error
+	switch {
+	case args.Hash != nil:
+		block, err = resolver.repository.FindBlockByHash(*args.Hash)
+	case args.Number != nil:
+		block, err = resolver.repository.FindBlockByNumber(int64(*args.Number))
+	default:
+		return nil, ErrMissingBlockLocator
+	}
+	if err != nil {
+		return nil, nil
+	}
+	return &blockResolver{repository: resolver.repository, block: block}, nil
+}
+
+func (resolver *queryResolver) Transactions(args struct {
+	Address string
+	First   *int32
+	After   *string
+}) (*transactionConnectionResolver, error) {
+	limit, offset, err := offsetPage(args.First, args.After)
+	if err != nil {
+		return nil, err
+	}
+	transactions := resolver.repository.FindTransactions(args.Address, limit+1, offset)
+	return newTransactionConnection(resolver.repository, transactions, limit, offset), nil
+}
+
+func (resolver *queryResolver) Logs(args struct {
+	Address   string
+	FromBlock *int32
+	ToBlock   *int32
+	First     *int32
+	After     *string
+}) (*logConnectionResolver, error) {
+	return findLogs(resolver.repository, args.Address, args.FromBlock, args.ToBlock, args.First, args.After)
+}
+
+func (resolver *queryResolver) Contract(args struct{ Hash string }) *contractResolver {
+	contract, err := resolver.repository.FindContract(args.Hash)
+	if err != nil {
+		return nil
+	}
+	return &contractResolver{repository: resolver.repository, contract: contract}
+}
+
+func (resolver *queryResolver) Contracts() []*contractResolver {
+	contracts := []*contractResolver{}
+	for _, contract := range resolver.repository.FindWatchedContracts() {
+		contracts = append(contracts, &contractResolver{repository: resolver.repository, contract: contract})
+	}
+	return contracts
+}
+
+type blockResolver struct {
+	repository repositories.Repository
+	block      core.Block
+}
+
+func (resolver *blockResolver) Number() int32 {
+	return int32(resolver.block.Number)
+}
+
+func (resolver *blockResolver) Hash() string {
+	return resolver.block.Hash
+}
+
+func (resolver *blockResolver) ParentHash() string {
+	return resolver.block.ParentHash
+}
+
+func (resolver *blockResolver) Nonce() string {
+	return resolver.block.Nonce
+}
+
+func (resolver *blockResolver) Time() int32 {
+	return int32(resolver.block.Time)
+}
+
+func (resolver *blockResolver) Size() int32 {
+	return int32(resolver.block.Size)
+}
+
+func (resolver *blockResolver) GasLimit() int32 {
+	return int32(resolver.block.GasLimit)
+}
+
+func (resolver *blockResolver) GasUsed() int32 {
+	return int32(resolver.block.GasUsed)
+}
+
+func (resolver *blockResolver) Difficulty() *string {
+	return bigIntString(resolver.block.Difficulty)
+}
+
+func (resolver *blockResolver) UncleHash() string {
+	return resolver.block.UncleHash
+}
+
+func (resolver *blockResolver) IsFinal() bool {
+	return resolver.block.IsFinal
+}
+
+func (resolver *blockResolver) Transactions() []*transactionResolver {
+	transactions := []*transactionResolver{}
+	for _, transaction := range resolver.block.Transactions {
+		transactions = append(transactions, &transactionResolver{repository: resolver.repository, transaction: transaction})
+	}
+	return transactions
+}
+
+type transactionResolver struct {
+	repository  repositories.Repository
+	transaction core.Transaction
+}
+
+func (resolver *transactionResolver) Hash() string {
+	return resolver.transaction.Hash
+}
+
+func (resolver *transactionResolver) Nonce() int32 {
+	return int32(resolver.transaction.Nonce)
+}
+
+func (resolver *transactionResolver) To() string {
+	return resolver.transaction.To
+}
+
+func (resolver *transactionResolver) From() string {
+	return resolver.transaction.From
+}
+
+func (resolver *transactionResolver) GasLimit() int32 {
+	return int32(resolver.transaction.GasLimit)
+}
+
+func (resolver *transactionResolver) GasPrice() *string {
+	return bigIntString(resolver.transaction.GasPrice)
+}
+
+func (resolver *transactionResolver) Value() *string {
+	return bigIntString(resolver.transaction.Value)
+}
+
+func (resolver *transactionResolver) Input() string {
+	return hexutil.Encode(resolver.transaction.Data)
+}
+
+func (resolver *transactionResolver) Receipt() *receiptResolver {
+	receipt, err := resolver.repository.FindReceipt(resolver.transaction.Hash)
+	if err != nil {
+		return nil
+	}
+	return &receiptResolver{receipt: receipt}
+}
+
+func (resolver *transactionResolver) Logs() []*logResolver {
+	receipt, err := resolver.repository.FindReceipt(resolver.transaction.Hash)
+	if err != nil {
+		return []*logResolver{}
+	}
+	return newLogResolvers(resolver.repository, receipt.Logs)
+}
+
+type receiptResolver struct {
+	receipt core.Receipt
+}
+
+func (resolver *receiptResolver) ContractAddress() string {
+	return resolver.receipt.ContractAddress
+}
+
+func (resolver *receiptResolver) CumulativeGasUsed() int32 {
+	return int32(resolver.receipt.CumulativeGasUsed)
+}
+
+func (resolver *receiptResolver) GasUsed() int32 {
+	return int32(resolver.receipt.GasUsed)
+}
+
+func (resolver *receiptResolver) StateRoot() string {
+	return resolver.receipt.StateRoot
+}
+
+func (resolver *receiptResolver) Status() int32 {
+	return int32(resolver.receipt.Status)
+}
+
+type logResolver struct {
+	repository repositories.Repository
+	log        core.Log
+}
+
+func newLogResolvers(repository repositories.Repository, logs []core.Log) []*logResolver {
+	resolvers := []*logResolver{}
+	for _, log := range logs {
+		resolvers = append(resolvers, &logResolver{repository: repository, log: log})
+	}
+	return resolvers
+}
+
+func (resolver *logResolver) BlockNumber() int32 {
+	return int32(resolver.log.BlockNumber)
+}
+
+func (resolver *logResolver) Index() int32 {
+	return int32(resolver.log.Index)
+}
+
+func (resolver *logResolver) Address() string {
+	return resolver.log.Address
+}
+
+func (resolver *logResolver) TxHash() string {
+	return resolver.log.TxHash
+}
+
+func (resolver *logResolver) Topics() []string {
+	return topics(resolver.log)
+}
+
+func (resolver *logResolver) Data() string {
+	return resolver.log.Data
+}
+
+func (resolver *logResolver) DecodedEvent() *decodedEventResolver {
+	event, err := resolver.repository.FindDecodedEvent(resolver.log.BlockNumber, resolver.log.Index)
+	if err != nil {
+		return nil
+	}
+	return &decodedEventResolver{event: event}
+}
+
+type decodedEventResolver struct {
+	event core.DecodedEvent
+}
+
+func (resolver *decodedEventResolver) Name() string {
+	return resolver.event.Name
+}
+
+func (resolver *decodedEventResolver) Arguments() []*eventArgumentResolver {
+	arguments := []*eventArgumentResolver{}
+	for _, argument := range resolver.event.Arguments {
+		arguments = append(arguments, &eventArgumentResolver{argument: argument})
+	}
+	return arguments
+}
+
+type eventArgumentResolver struct {
+	argument core.EventArgument
+}
+
+func (resolver *eventArgumentResolver) Name() string {
+	return resolver.argument.Name
+}
+
+func (resolver *eventArgumentResolver) Type() string {
+	return resolver.argument.Type
+}
+
+func (resolver *eventArgumentResolver) Indexed() bool {
+	return resolver.argument.Indexed
+}
+
+func (resolver *eventArgumentResolver) Value() string {
+	return resolver.argument.Value
+}
+
+// contractResolver fetches the transactions of the contract only when they
+// are queried, so its contract.Transactions are not used.
+type contractResolver struct {
+	repository repositories.Repository
+	contract   core.Contract
+}
+
+func (resolver *contractResolver) Hash() string {
+	return resolver.contract.Hash
+}
+
+func (resolver *contractResolver) Abi() string {
+	return resolver.contract.Abi
+}
+
+func (resolver *contractResolver) NumberOfTransactions() int32 {
+	return int32(resolver.repository.TransactionCount(resolver.contract.Hash))
+}
+
+func (resolver *contractResolver) Transactions(args struct {
+	First *int32
+	After *string
+}) (*transactionConnectionResolver, error) {
+	limit, offset, err := offsetPage(args.First, args.After)
+	if err != nil {
+		return nil, err
+	}
+	transactions := resolver.repository.FindTransactions(resolver.contract.Hash, limit+1, offset)
+	return newTransactionConnection(resolver.repository, transactions, limit, offset), nil
+}
+
+func (resolver *contractResolver) Logs(args struct {
+	FromBlock *int32
+	ToBlock   *int32
+	First     *int32
+	After     *string
+}) (*logConnectionResolver, error) {
+	return findLogs(resolver.repository, resolver.contract.Hash, args.FromBlock, args.ToBlock, args.First, args.After)
+}
+
+type pageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (resolver *pageInfoResolver) HasNextPage() bool {
+	return resolver.hasNextPage
+}
+
+func (resolver *pageInfoResolver) EndCursor() *string {
+	return resolver.endCursor
+}
+
+type transactionConnectionResolver struct {
+	edges    []*transactionEdgeResolver
+	pageInfo *pageInfoResolver
+}
+
+// newTransactionConnection pages transactions fetched with one more than the
+// limit, so that the extra transaction tells whether there is a next page.
+func newTransactionConnection(repository repositories.Repository, transactions []core.Transaction, limit int, offset int) *transactionConnectionResolver {
+	connection := &transactionConnectionResolver{edges: []*transactionEdgeResolver{}, pageInfo: &pageInfoResolver{}}
+	for i, transaction := range transactions {
+		if i == limit {
+			connection.pageInfo.hasNextPage = true
+			break
+		}
+		cursor := encodeCursor("offset", int64(offset+i+1))
+		connection.edges = append(connection.edges, &transactionEdgeResolver{
+			cursor: cursor,
+			node:   &transactionResolver{repository: repository, transaction: transaction},
+		})
+		connection.pageInfo.endCursor = &cursor
+	}
+	return connection
+}
+
+func (resolver *transactionConnectionResolver) Edges() []*transactionEdgeResolver {
+	return resolver.edges
+}
+
+func (resolver *transactionConnectionResolver) PageInfo() *pageInfoResolver {
+	return resolver.pageInfo
+}
+
+type transactionEdgeResolver struct {
+	cursor string
+	node   *transactionResolver
+}
+
+func (resolver *transactionEdgeResolver) Cursor() string {
+	return resolver.cursor
+}
+
+func (resolver *transactionEdgeResolver) Node() *transactionResolver {
+	return resolver.node
+}
+
+type logConnectionResolver struct {
+	edges    []*logEdgeResolver
+	pageInfo *pageInfoResolver
+}
+
+func findLogs(repository repositories.Repository, address string, fromBlock *int32, toBlock *int32, first *int32, after *string) (*logConnectionResolver, error) {
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	startingBlockNumber := int64(0)
+	if fromBlock != nil {
+		startingBlockNumber = int64(*fromBlock)
+	}
+	endingBlockNumber := repository.MaxBlockNumber()
+	if toBlock != nil {
+		endingBlockNumber = int64(*toBlock)
+	}
+	afterPosition := core.LogPosition{BlockNumber: -1, Index: -1}
+	if after != nil {
+		position, err := decodeCursor(*after, "log", 2)
+		if err != nil {
+			return nil, err
+		}
+		afterPosition = core.LogPosition{BlockNumber: position[0], Index: position[1]}
+	}
+	connection := &logConnectionResolver{edges: []*logEdgeResolver{}, pageInfo: &pageInfoResolver{}}
+	// one more log than the limit tells whether there is a next page
+	for _, log := range repository.FindLogsPage(address, startingBlockNumber, endingBlockNumber, afterPosition, limit+1) {
+		if len(connection.edges) == limit {
+			connection.pageInfo.hasNextPage = true
+			break
+		}
+		cursor := encodeCursor("log", log.BlockNumber, log.Index)
+		connection.edges = append(connection.edges, &logEdgeResolver{
+			cursor: cursor,
+			node:   &logResolver{repository: repository, log: log},
+		})
+		connection.pageInfo.endCursor = &cursor
+	}
+	return connection, nil
+}
+
+func (resolver *logConnectionResolver) Edges() []*logEdgeResolver {
+	return resolver.edges
+}
+
+func (resolver *logConnectionResolver) PageInfo() *pageInfoResolver {
+	return resolver.pageInfo
+}
+
+type logEdgeResolver struct {
+	cursor string
+	node   *logResolver
+}
+
+func (resolver *logEdgeResolver) Cursor() string {
+	return resolver.cursor
+}
+
+func (resolver *logEdgeResolver) Node() *logResolver {
+	return resolver.node
+}
+
+func pageSize(first *int32) (int, error) {
+	if first == nil {
+		return defaultPageSize, nil
+	}
+	if *first < 1 || *first > maxPageSize {
+		return 0, ErrInvalidPagination
+	}
+	return int(*first), nil
+}
+
+func offsetPage(first *int32, after *string) (int, int, error) {
+	limit, err := pageSize(first)
+	if err != nil {
+		return 0, 0, err
+	}
+	if after == nil {
+		return limit, 0, nil
+	}
+	position, err := decodeCursor(*after, "offset", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit, int(position[0]), nil
+}
+
+// Cursors are opaque to clients: the kind of position and its values,
+// base64 encoded, e.g. "log:4703824:19".
+func encodeCursor(kind string, values ...int64) string {
+	cursor := kind
+	for _, value := range values {
+		cursor += fmt.Sprintf(":%d", value)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(cursor))
+}
+
+func decodeCursor(cursor string, kind string, numberOfValues int) ([]int64, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var decodedKind string
+	values := make([]int64, numberOfValues)
+	format := "%s"
+	arguments := []interface{}{&decodedKind}
+	for i := range values {
+		format += " %d"
+		arguments = append(arguments, &values[i])
+	}
+	_, err = fmt.Sscanf(strings.Replace(string(decoded), ":", " ", -1), format, arguments...)
+	if err != nil || decodedKind != kind {
+		return nil, ErrInvalidCursor
+	}
+	for _, value := range values {
+		if value < 0 {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
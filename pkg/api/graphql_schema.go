@@ -0,0 +1,109 @@
+package api
+
+const graphQLSchema = `
+	schema {
+		query: Query
+	}
+
+	type Query {
+		# A block by its number or its hash
+		block(number: Int, hash: String): Block
+		# Transactions sent to or from an address, most recent block first
+		transactions(address: String!, first: Int, after: String): TransactionConnection!
+		# Logs emitted by an address within a block range
+		logs(address: String!, fromBlock: Int, toBlock: Int, first: Int, after: String): LogConnection!
+		contract(hash: String!): Contract
+		contracts: [Contract!]!
+	}
+
+	type Block {
+		number: Int!
+		hash: String!
+		parentHash: String!
+		nonce: String!
+		time: Int!
+		size: Int!
+		gasLimit: Int!
+		gasUsed: Int!
+		difficulty: String
+		uncleHash: String!
+		isFinal: Boolean!
+		transactions: [Transaction!]!
+	}
+
+	type Transaction {
+		hash: String!
+		nonce: Int!
+		to: String!
+		from: String!
+		gasLimit: Int!
+		gasPrice: String
+		value: String
+		input: String!
+		receipt: Receipt
+		logs: [Log!]!
+	}
+
+	type Receipt {
+		contractAddress: String!
+		cumulativeGasUsed: Int!
+		gasUsed: Int!
+		stateRoot: String!
+		status: Int!
+	}
+
+	type Log {
+		blockNumber: Int!
+		index: Int!
+		address: String!
+		txHash: String!
+		topics: [String!]!
+		data: String!
+		decodedEvent: DecodedEvent
+	}
+
+	type DecodedEvent {
+		name: String!
+		arguments: [EventArgument!]!
+	}
+
+	type EventArgument {
+		name: String!
+		type: String!
+		indexed: Boolean!
+		value: String!
+	}
+
+	type Contract {
+		hash: String!
+		abi: String!
+		numberOfTransactions: Int!
+		transactions(first: Int, after: String): TransactionConnection!
+		logs(fromBlock: Int, toBlock: Int, first: Int, after: String): LogConnection!
+	}
+
+	type PageInfo {
+		hasNextPage: Boolean!
+		endCursor: String
+	}
+
+	type TransactionConnection {
+		edges: [TransactionEdge!]!
+		pageInfo: PageInfo!
+	}
+
+	type TransactionEdge {
+		cursor: String!
+		node: Transaction!
+	}
+
+	type LogConnection {
+		edges: [LogEdge!]!
+		pageInfo: PageInfo!
+	}
+
+	type LogEdge {
+		cursor: String!
+		node: Log!
+	}
+`
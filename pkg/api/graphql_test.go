@@ -0,0 +1,213 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/vulcanize/vulcanizedb/pkg/api"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("The GraphQL API", func() {
+	var repository *repositories.InMemory
+	var server *httptest.Server
+
+	query := func(query string, variables map[string]interface{}) string {
+		request, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+		response, err := http.Post(server.URL, "application/json", bytes.NewReader(request))
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		body, _ := ioutil.ReadAll(response.Body)
+		return string(body)
+	}
+
+	transferLog := func(blockNumber int64, index int64, txHash string) core.Log {
+		return core.Log{
+			BlockNumber: blockNumber,
+			Index:       index,
+			Address:     "0x123",
+			TxHash:      txHash,
+			Topics:      map[int]string{0: "0xtopic0"},
+			Data:        "0xdata",
+		}
+	}
+
+	BeforeEach(func() {
+		repository = repositories.NewInMemory()
+		repository.CreateContract(core.Contract{Hash: "0x123", Abi: "[]"})
+		repository.CreateOrUpdateBlock(core.Block{
+			Number: 1,
+			Hash:   "0xabc",
+			Transactions: []core.Transaction{{
+				Hash: "0x111",
+				To:   "0x123",
+				From: "0x456",
+				Receipt: core.Receipt{
+					TxHash:  "0x111",
+					GasUsed: 21000,
+					Status:  1,
+					Logs:    []core.Log{transferLog(1, 0, "0x111"), transferLog(1, 1, "0x111")},
+				},
+			}},
+		})
+		repository.CreateOrUpdateBlock(core.Block{
+			Number: 2,
+			Hash:   "0xdef",
+			Transactions: []core.Transaction{{
+				Hash:    "0x222",
+				To:      "0x123",
+				From:    "0x789",
+				Receipt: core.Receipt{TxHash: "0x222", Logs: []core.Log{transferLog(2, 0, "0x222")}},
+			}},
+		})
+		repository.CreateDecodedEvents([]core.DecodedEvent{{
+			Address:     "0x123",
+			BlockNumber: 1,
+			LogIndex:    0,
+			TxHash:      "0x111",
+			Name:        "Transfer",
+			Arguments:   []core.EventArgument{{Name: "value", Type: "uint256", Value: "1000"}},
+		}})
+		server = httptest.NewServer(api.NewGraphQLHandler(repository))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("fetches a block with its transactions, logs and decoded events", func() {
+		response := query(`{
+			block(number: 1) {
+				hash
+				transactions {
+					hash
+					receipt { gasUsed status }
+					logs {
+						index
+						topics
+						decodedEvent { name arguments { name type value } }
+					}
+				}
+			}
+		}`, nil)
+
+		Expect(response).To(MatchJSON(`{"data": {"block": {
+			"hash": "0xabc",
+			"transactions": [{
+				"hash": "0x111",
+				"receipt": {"gasUsed": 21000, "status": 1},
+				"logs": [
+					{"index": 0, "topics": ["0xtopic0"], "decodedEvent": {"name": "Transfer", "arguments": [{"name": "value", "type": "uint256", "value": "1000"}]}},
+					{"index": 1, "topics": ["0xtopic0"], "decodedEvent": null}
+				]
+			}]
+		}}}`))
+	})
+
+	It("fetches a block by hash", func() {
+		response := query(`query($hash: String) { block(hash: $hash) { number } }`, map[string]interface{}{"hash": "0xdef"})
+
+		Expect(response).To(MatchJSON(`{"data": {"block": {"number": 2}}}`))
+	})
+
+	It("returns null for a missing block", func() {
+		response := query(`{ block(number: 3) { number } }`, nil)
+
+		Expect(response).To(MatchJSON(`{"data": {"block": null}}`))
+	})
+
+	It("pages through the transactions of an address", func() {
+		firstPage := query(`{
+			transactions(address: "0x123", first: 1) {
+				edges { node { hash } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}`, nil)
+		var decoded struct {
+			Data struct {
+				Transactions struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				}
+			}
+		}
+		json.Unmarshal([]byte(firstPage), &decoded)
+		Expect(firstPage).To(ContainSubstring(`"hash":"0x222"`))
+		Expect(decoded.Data.Transactions.PageInfo.HasNextPage).To(BeTrue())
+
+		secondPage := query(`query($after: String) {
+			transactions(address: "0x123", first: 1, after: $after) {
+				edges { node { hash } }
+				pageInfo { hasNextPage }
+			}
+		}`, map[string]interface{}{"after": decoded.Data.Transactions.PageInfo.EndCursor})
+
+		Expect(secondPage).To(MatchJSON(`{"data": {"transactions": {
+			"edges": [{"node": {"hash": "0x111"}}],
+			"pageInfo": {"hasNextPage": false}
+		}}}`))
+	})
+
+	It("filters logs by block range and pages through them", func() {
+		firstPage := query(`{
+			logs(address: "0x123", fromBlock: 1, toBlock: 2, first: 2) {
+				edges { cursor node { blockNumber index } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}`, nil)
+		var decoded struct {
+			Data struct {
+				Logs struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				}
+			}
+		}
+		json.Unmarshal([]byte(firstPage), &decoded)
+		Expect(decoded.Data.Logs.PageInfo.HasNextPage).To(BeTrue())
+
+		secondPage := query(`query($after: String) {
+			logs(address: "0x123", fromBlock: 1, toBlock: 2, after: $after) {
+				edges { node { blockNumber index } }
+			}
+		}`, map[string]interface{}{"after": decoded.Data.Logs.PageInfo.EndCursor})
+		Expect(secondPage).To(MatchJSON(`{"data": {"logs": {"edges": [{"node": {"blockNumber": 2, "index": 0}}]}}}`))
+
+		onlySecondBlock := query(`{ logs(address: "0x123", fromBlock: 2) { edges { node { blockNumber } } } }`, nil)
+		Expect(onlySecondBlock).To(MatchJSON(`{"data": {"logs": {"edges": [{"node": {"blockNumber": 2}}]}}}`))
+	})
+
+	It("fetches watched contracts with their transactions and logs", func() {
+		response := query(`{
+			contracts {
+				hash
+				numberOfTransactions
+				transactions(first: 1) { pageInfo { hasNextPage } }
+				logs(toBlock: 1) { edges { node { index } } }
+			}
+		}`, nil)
+
+		Expect(response).To(MatchJSON(`{"data": {"contracts": [{
+			"hash": "0x123",
+			"numberOfTransactions": 2,
+			"transactions": {"pageInfo": {"hasNextPage": true}},
+			"logs": {"edges": [{"node": {"index": 0}}, {"node": {"index": 1}}]}
+		}]}}`))
+	})
+
+	It("returns an error for an invalid cursor", func() {
+		response := query(`{ logs(address: "0x123", after: "not a cursor") { edges { cursor } } }`, nil)
+
+		Expect(response).To(ContainSubstring(api.ErrInvalidCursor.Error()))
+	})
+})
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type block struct {
+	Number       int64         `json:"number"`
+	Hash         string        `json:"hash"`
+	ParentHash   string        `json:"parent_hash"`
+	Nonce        string        `json:"nonce"`
+	Time         int64         `json:"time"`
+	Size         int64         `json:"size"`
+	GasLimit     int64         `json:"gas_limit"`
+	GasUsed      int64         `json:"gas_used"`
+	Difficulty   *string       `json:"difficulty"`
+	UncleHash    string        `json:"uncle_hash"`
+	IsFinal      bool          `json:"is_final"`
+	Transactions []transaction `json:"transactions"`
+}
+
+type transaction struct {
+	Hash     string  `json:"hash"`
+	Nonce    uint64  `json:"nonce"`
+	To       string  `json:"to"`
+	From     string  `json:"from"`
+	GasLimit int64   `json:"gas_limit"`
+	GasPrice *string `json:"gas_price"`
+	Value    *string `json:"value"`
+	Input    string  `json:"input"`
+}
+
+type transactionPage struct {
+	Address      string        `json:"address"`
+	Limit        int           `json:"limit"`
+	Offset       int           `json:"offset"`
+	Transactions []transaction `json:"transactions"`
+}
+
+type log struct {
+	BlockNumber int64    `json:"block_number"`
+	Index       int64    `json:"index"`
+	Address     string   `json:"address"`
+	TxHash      string   `json:"tx_hash"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+}
+
+type logRange struct {
+	Address   string `json:"address"`
+	FromBlock int64  `json:"from_block"`
+	ToBlock   int64  `json:"to_block"`
+	Logs      []log  `json:"logs"`
+}
+
+type syncStatus struct {
+	ChainHead    int64 `json:"chain_head"`
+	HighestBlock int64 `json:"highest_block"`
+	BlockCount   int   `json:"block_count"`
+	BlocksBehind int64 `json:"blocks_behind"`
+}
+
+func presentBlock(coreBlock core.Block) block {
+	return block{
+		Number:       coreBlock.Number,
+		Hash:         coreBlock.Hash,
+		ParentHash:   coreBlock.ParentHash,
+		Nonce:        coreBlock.Nonce,
+		Time:         coreBlock.Time,
+		Size:         coreBlock.Size,
+		GasLimit:     coreBlock.GasLimit,
+		GasUsed:      coreBlock.GasUsed,
+		Difficulty:   bigIntString(coreBlock.Difficulty),
+		UncleHash:    coreBlock.UncleHash,
+		IsFinal:      coreBlock.IsFinal,
+		Transactions: presentTransactions(coreBlock.Transactions),
+	}
+}
+
+func presentTransactions(coreTransactions []core.Transaction) []transaction {
+	transactions := []transaction{}
+	for _, coreTransaction := range coreTransactions {
+		transactions = append(transactions, transaction{
+			Hash:     coreTransaction.Hash,
+			Nonce:    coreTransaction.Nonce,
+			To:       coreTransaction.To,
+			From:     coreTransaction.From,
+			GasLimit: coreTransaction.GasLimit,
+			GasPrice: bigIntString(coreTransaction.GasPrice),
+			Value:    bigIntString(coreTransaction.Value),
+			Input:    hexutil.Encode(coreTransaction.Data),
+		})
+	}
+	return transactions
+}
+
+func presentLogs(coreLogs []core.Log) []log {
+	logs := []log{}
+	for _, coreLog := range coreLogs {
+		logs = append(logs, log{
+			BlockNumber: coreLog.BlockNumber,
+			Index:       coreLog.Index,
+			Address:     coreLog.Address,
+			TxHash:      coreLog.TxHash,
+			Topics:      topics(coreLog),
+			Data:        coreLog.Data,
+		})
+	}
+	return logs
+}
+
+func topics(coreLog core.Log) []string {
+	var positions []int
+	for position, topic := range coreLog.Topics {
+		if topic != "" {
+			positions = append(positions, position)
+		}
+	}
+	sort.Ints(positions)
+	topics := []string{}
+	for _, position := range positions {
+		topics = append(topics, coreLog.Topics[position])
+	}
+	return topics
+}
+
+func bigIntString(value *big.Int) *string {
+	if value == nil {
+		return nil
+	}
+	stringValue := value.String()
+	return &stringValue
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_summary"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+)
+
+const (
+	defaultPageSize = 25
+	maxPageSize     = 100
+)
+
+var (
+	ErrInvalidBlockNumber = errors.New("invalid block number")
+	ErrInvalidPagination  = errors.New("invalid limit or offset")
+	ErrNotFound           = errors.New("not found")
+)
+
+// Server answers read-only HTTP/JSON queries about the indexed chain data:
+//
+//	GET /blocks/{number or hash}
+//	GET /addresses/{address}/transactions?limit=&offset=
+//	GET /contracts/{hash}/logs?from_block=&to_block=
+//	GET /contracts/{hash}/summary?block_number=
+//	GET /status
+//	POST /graphql
+type Server struct {
+	blockchain core.Blockchain
+	repository repositories.Repository
+	mux        *http.ServeMux
+}
+
+func NewServer(blockchain core.Blockchain, repository repositories.Repository) *Server {
+	server := &Server{blockchain: blockchain, repository: repository, mux: http.NewServeMux()}
+	server.mux.HandleFunc("/blocks/", server.block)
+	server.mux.HandleFunc("/addresses/", server.transactions)
+	server.mux.HandleFunc("/contracts/", server.contract)
+	server.mux.HandleFunc("/status", server.status)
+	server.mux.Handle("/graphql", NewGraphQLHandler(repository))
+	return server
+}
+
+func (server *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.URL.Path == "/graphql" {
+		if request.Method != http.MethodPost {
+			writeError(writer, http.StatusMethodNotAllowed, errors.New("graphql queries must be POSTed"))
+			return
+		}
+	} else if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, errors.New("only GET requests are supported"))
+		return
+	}
+	server.mux.ServeHTTP(writer, request)
+}
+
+func (server *Server) block(writer http.ResponseWriter, request *http.Request) {
+	segments := pathSegments(request, "/blocks/")
+	if len(segments) != 1 {
+		writeError(writer, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	var block core.Block
+	var err error
+	if strings.HasPrefix(segments[0], "0x") {
+		block, err = server.repository.FindBlockByHash(segments[0])
+	} else {
+		blockNumber, parseErr := strconv.ParseInt(segments[0], 10, 64)
+		if parseErr != nil {
+			writeError(writer, http.StatusBadRequest, ErrInvalidBlockNumber)
+			return
+		}
+		block, err = server.repository.FindBlockByNumber(blockNumber)
+	}
+	if err != nil {
+		writeError(writer, http.StatusNotFound, err)
+		return
+	}
+	writeJson(writer, presentBlock(block))
+}
+
+func (server *Server) transactions(writer http.ResponseWriter, request *http.Request) {
+	segments := pathSegments(request, "/addresses/")
+	if len(segments) != 2 || segments[1] != "transactions" {
+		writeError(writer, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	limit, offset, err := pagination(request)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+	transactions := server.repository.FindTransactions(segments[0], limit, offset)
+	writeJson(writer, transactionPage{
+		Address:      segments[0],
+		Limit:        limit,
+		Offset:       offset,
+		Transactions: presentTransactions(transactions),
+	})
+}
+
+func (server *Server) contract(writer http.ResponseWriter, request *http.Request) {
+	segments := pathSegments(request, "/contracts/")
+	if len(segments) != 2 {
+		writeError(writer, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	switch segments[1] {
+	case "logs":
+		server.logs(writer, request, segments[0])
+	case "summary":
+		server.summary(writer, request, segments[0])
+	default:
+		writeError(writer, http.StatusNotFound, ErrNotFound)
+	}
+}
+
+func (server *Server) logs(writer http.ResponseWriter, request *http.Request, contractHash string) {
+	fromBlock, err := blockNumberParameter(request, "from_block", 0)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+	toBlock, err := blockNumberParameter(request, "to_block", server.repository.MaxBlockNumber())
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+	logs := server.repository.FindLogsInRange(contractHash, fromBlock, toBlock)
+	writeJson(writer, logRange{
+		Address:   contractHash,
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Logs:      presentLogs(logs),
+	})
+}
+
+func (server *Server) summary(writer http.ResponseWriter, request *http.Request, contractHash string) {
+	var blockNumber *big.Int
+	if request.URL.Query().Get("block_number") != "" {
+		number, err := blockNumberParameter(request, "block_number", 0)
+		if err != nil {
+			writeError(writer, http.StatusBadRequest, err)
+			return
+		}
+		blockNumber = big.NewInt(number)
+	}
+	summary, err := contract_summary.NewSummary(request.Context(), server.blockchain, server.repository, contractHash, blockNumber)
+	if err != nil && !server.repository.ContractExists(contractHash) {
+		writeError(writer, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(writer, http.StatusBadGateway, err)
+		return
+	}
+	output, err := contract_summary.GenerateJsonOutput(request.Context(), summary)
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, err)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write([]byte(output))
+}
+
+func (server *Server) status(writer http.ResponseWriter, request *http.Request) {
+	lastBlock, err := server.blockchain.LastBlock(request.Context())
+	if err != nil {
+		writeError(writer, http.StatusBadGateway, err)
+		return
+	}
+	chainHead := lastBlock.Int64()
+	highestBlock := server.repository.MaxBlockNumber()
+	writeJson(writer, syncStatus{
+		ChainHead:    chainHead,
+		HighestBlock: highestBlock,
+		BlockCount:   server.repository.BlockCount(),
+		BlocksBehind: chainHead - highestBlock,
+	})
+}
+
+func pathSegments(request *http.Request, prefix string) []string {
+	path := strings.Trim(strings.TrimPrefix(request.URL.Path, prefix), "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func pagination(request *http.Request) (int, int, error) {
+	limit := defaultPageSize
+	offset := 0
+	query := request.URL.Query()
+	var err error
+	if query.Get("limit") != "" {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return 0, 0, ErrInvalidPagination
+		}
+	}
+	if query.Get("offset") != "" {
+		offset, err = strconv.Atoi(query.Get("offset"))
+		if err != nil || offset < 0 {
+			return 0, 0, ErrInvalidPagination
+		}
+	}
+	return limit, offset, nil
+}
+
+func blockNumberParameter(request *http.Request, name string, defaultValue int64) (int64, error) {
+	value := request.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	blockNumber, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || blockNumber < 0 {
+		return 0, ErrInvalidBlockNumber
+	}
+	return blockNumber, nil
+}
+
+func writeJson(writer http.ResponseWriter, value interface{}) {
+	output, err := json.Marshal(value)
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, err)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(output)
+}
+
+func writeError(writer http.ResponseWriter, status int, err error) {
+	output, _ := json.Marshal(errorResponse{Error: err.Error()})
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	writer.Write(output)
+}
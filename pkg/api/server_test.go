@@ -0,0 +1,245 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/vulcanize/vulcanizedb/pkg/api"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("The HTTP API", func() {
+	var blockchain *fakes.Blockchain
+	var repository *repositories.InMemory
+	var server *httptest.Server
+
+	get := func(path string) (int, string) {
+		response, err := http.Get(server.URL + path)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		body, _ := ioutil.ReadAll(response.Body)
+		return response.StatusCode, string(body)
+	}
+
+	BeforeEach(func() {
+		blockchain = fakes.NewBlockchain()
+		repository = repositories.NewInMemory()
+		repository.CreateOrUpdateBlock(core.Block{
+			Number:     1,
+			Hash:       "0xabc",
+			ParentHash: "0x000",
+			Difficulty: big.NewInt(10),
+			Transactions: []core.Transaction{{
+				Hash:     "0x111",
+				To:       "0x123",
+				From:     "0x456",
+				GasLimit: 21000,
+				GasPrice: big.NewInt(5),
+				Value:    big.NewInt(100),
+				Data:     []byte{1, 2},
+				Receipt: core.Receipt{
+					TxHash: "0x111",
+					Logs: []core.Log{{
+						BlockNumber: 1,
+						Index:       0,
+						Address:     "0x123",
+						TxHash:      "0x111",
+						Topics:      map[int]string{0: "0xtopic0", 1: "0xtopic1", 2: "", 3: ""},
+						Data:        "0xdata",
+					}},
+				},
+			}},
+		})
+		repository.CreateOrUpdateBlock(core.Block{
+			Number: 2,
+			Hash:   "0xdef",
+			Transactions: []core.Transaction{
+				{Hash: "0x222", To: "0x789", From: "0x123"},
+				{Hash: "0x333", To: "0x789", From: "0x789"},
+			},
+		})
+		server = httptest.NewServer(api.NewServer(blockchain, repository))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("blocks", func() {
+		It("returns a block by number", func() {
+			status, body := get("/blocks/1")
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`{
+				"number": 1,
+				"hash": "0xabc",
+				"parent_hash": "0x000",
+				"nonce": "",
+				"time": 0,
+				"size": 0,
+				"gas_limit": 0,
+				"gas_used": 0,
+				"difficulty": "10",
+				"uncle_hash": "",
+				"is_final": false,
+				"transactions": [{
+					"hash": "0x111",
+					"nonce": 0,
+					"to": "0x123",
+					"from": "0x456",
+					"gas_limit": 21000,
+					"gas_price": "5",
+					"value": "100",
+					"input": "0x0102"
+				}]
+			}`))
+		})
+
+		It("returns a block by hash", func() {
+			status, body := get("/blocks/0xdef")
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(ContainSubstring(`"number":2`))
+		})
+
+		It("returns not found for a missing block", func() {
+			status, body := get("/blocks/3")
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			Expect(body).To(MatchJSON(`{"error": "Block number 3 does not exist"}`))
+		})
+
+		It("rejects an invalid block number", func() {
+			status, _ := get("/blocks/one")
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("transactions by address", func() {
+		It("returns the transactions to or from the address", func() {
+			status, body := get("/addresses/0x123/transactions")
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(ContainSubstring(`"limit":25,"offset":0`))
+			Expect(body).To(MatchRegexp(`"hash":"0x222".*"hash":"0x111"`))
+		})
+
+		It("pages through the transactions", func() {
+			_, body := get("/addresses/0x123/transactions?limit=1&offset=1")
+
+			Expect(body).To(ContainSubstring(`"hash":"0x111"`))
+			Expect(body).NotTo(ContainSubstring(`"hash":"0x222"`))
+		})
+
+		It("returns an empty list past the last page", func() {
+			_, body := get("/addresses/0x123/transactions?offset=10")
+
+			Expect(body).To(ContainSubstring(`"transactions":[]`))
+		})
+
+		It("rejects an invalid limit", func() {
+			status, _ := get("/addresses/0x123/transactions?limit=1000")
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("contract logs", func() {
+		It("returns the logs of the contract in the block range", func() {
+			status, body := get("/contracts/0x123/logs?from_block=1&to_block=2")
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`{
+				"address": "0x123",
+				"from_block": 1,
+				"to_block": 2,
+				"logs": [{
+					"block_number": 1,
+					"index": 0,
+					"address": "0x123",
+					"tx_hash": "0x111",
+					"topics": ["0xtopic0", "0xtopic1"],
+					"data": "0xdata"
+				}]
+			}`))
+		})
+
+		It("defaults the range to every saved block", func() {
+			_, body := get("/contracts/0x123/logs")
+
+			Expect(body).To(ContainSubstring(`"from_block":0,"to_block":2`))
+		})
+
+		It("returns no logs outside the block range", func() {
+			_, body := get("/contracts/0x123/logs?from_block=2")
+
+			Expect(body).To(ContainSubstring(`"logs":[]`))
+		})
+	})
+
+	Describe("contract summaries", func() {
+		It("returns the summary of a watched contract", func() {
+			repository.CreateContract(core.Contract{Hash: "0x123"})
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+
+			status, body := get("/contracts/0x123/summary")
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`{
+				"contract_hash": "0x123",
+				"block_number": null,
+				"number_of_transactions": 1,
+				"last_transaction": {"hash": "0x111", "to": "0x123", "from": "0x456"},
+				"attributes": [{"name": "foo", "type": "string", "value": "bar"}]
+			}`))
+		})
+
+		It("returns not found for a contract that is not watched", func() {
+			status, _ := get("/contracts/0x999/summary")
+
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("sync status", func() {
+		It("compares the saved blocks with the chain head", func() {
+			server.Close()
+			blockchain := fakes.NewBlockchainWithBlocks([]core.Block{{Number: 5}})
+			server = httptest.NewServer(api.NewServer(blockchain, repository))
+
+			status, body := get("/status")
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`{
+				"chain_head": 5,
+				"highest_block": 2,
+				"block_count": 2,
+				"blocks_behind": 3
+			}`))
+		})
+
+		It("returns bad gateway when the node cannot be reached", func() {
+			blockchain.SetLastBlockError(errors.New("connection refused"))
+
+			status, body := get("/status")
+
+			Expect(status).To(Equal(http.StatusBadGateway))
+			Expect(body).To(MatchJSON(`{"error": "connection refused"}`))
+		})
+	})
+
+	It("only answers GET requests", func() {
+		response, err := http.Post(server.URL+"/status", "application/json", nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
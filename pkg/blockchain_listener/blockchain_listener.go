@@ -1,34 +1,149 @@
 package blockchain_listener
 
-import "github.com/vulcanize/vulcanizedb/pkg/core"
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+const (
+	reconnectDelay    = time.Second
+	maxReconnectDelay = time.Minute
+	// Largest number of missed blocks requested from the node at once.
+	catchUpBatchSize = 100
+)
 
 type BlockchainListener struct {
 	inputBlocks chan core.Block
 	blockchain  core.Blockchain
 	observers   []core.BlockchainObserver
+	// ReconnectDelay is the wait before resubscribing when the subscription
+	// fails, doubling after each failed attempt up to MaxReconnectDelay.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
 }
 
-func NewBlockchainListener(blockchain core.Blockchain, observers []core.BlockchainObserver) BlockchainListener {
+func NewBlockchainListener(ctx context.Context, blockchain core.Blockchain, observers []core.BlockchainObserver) (BlockchainListener, error) {
 	inputBlocks := make(chan core.Block, 10)
-	blockchain.SubscribeToBlocks(inputBlocks)
+	err := blockchain.SubscribeToBlocks(ctx, inputBlocks)
+	if err != nil {
+		return BlockchainListener{}, err
+	}
 	listener := BlockchainListener{
-		inputBlocks: inputBlocks,
-		blockchain:  blockchain,
-		observers:   observers,
+		inputBlocks:       inputBlocks,
+		blockchain:        blockchain,
+		observers:         observers,
+		ReconnectDelay:    reconnectDelay,
+		MaxReconnectDelay: maxReconnectDelay,
+	}
+	return listener, nil
+}
+
+// Start notifies the observers of each new block until the context is done
+// or the listener is stopped. When the subscription fails, it resubscribes
+// and notifies the observers of the blocks missed in the meantime.
+func (listener BlockchainListener) Start(ctx context.Context) error {
+	lastBlockNumber := int64(-1)
+	// blocks notified while catching up, which the new subscription may
+	// deliver again
+	caughtUp := make(map[int64]string)
+	notify := func(block core.Block) {
+		if hash, ok := caughtUp[block.Number]; ok && hash == block.Hash {
+			return
+		}
+		listener.notifyObservers(ctx, block)
+		if block.Number > lastBlockNumber {
+			lastBlockNumber = block.Number
+		}
+	}
+	for {
+		err := listener.listen(ctx, notify)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		log.Printf("Subscription to new blocks failed, reconnecting\n%v", err)
+		err = listener.resubscribe(ctx)
+		if err != nil {
+			return err
+		}
+		caughtUp = make(map[int64]string)
+		for _, block := range listener.missedBlocks(ctx, lastBlockNumber) {
+			notify(block)
+			caughtUp[block.Number] = block.Hash
+		}
 	}
-	return listener
 }
 
-func (listener BlockchainListener) Start() {
-	go listener.blockchain.StartListening()
-	for block := range listener.inputBlocks {
-		listener.notifyObservers(block)
+// listen notifies the observers of the blocks from the subscription until
+// the blockchain stops listening, returning the reason it stopped.
+func (listener BlockchainListener) listen(ctx context.Context, notify func(block core.Block)) error {
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- listener.blockchain.StartListening(ctx)
+	}()
+	for {
+		select {
+		case block := <-listener.inputBlocks:
+			notify(block)
+		case err := <-stopped:
+			return err
+		}
+	}
+}
+
+func (listener BlockchainListener) resubscribe(ctx context.Context) error {
+	delay := listener.ReconnectDelay
+	for {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		err := listener.blockchain.SubscribeToBlocks(ctx, listener.inputBlocks)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Error resubscribing to new blocks, retrying in %v\n%v", delay, err)
+		delay *= 2
+		if delay > listener.MaxReconnectDelay {
+			delay = listener.MaxReconnectDelay
+		}
+	}
+}
+
+// missedBlocks retrieves the blocks after lastBlockNumber up to the chain
+// head. It stops at the first batch that cannot be retrieved, leaving the
+// rest to the backfill.
+func (listener BlockchainListener) missedBlocks(ctx context.Context, lastBlockNumber int64) []core.Block {
+	if lastBlockNumber < 0 {
+		return nil
+	}
+	head, err := listener.blockchain.LastBlock(ctx)
+	if err != nil {
+		log.Printf("Error retrieving the blocks missed while disconnected\n%v", err)
+		return nil
+	}
+	var missed []core.Block
+	for start := lastBlockNumber + 1; start <= head.Int64(); start += catchUpBatchSize {
+		var blockNumbers []int64
+		for blockNumber := start; blockNumber < start+catchUpBatchSize && blockNumber <= head.Int64(); blockNumber++ {
+			blockNumbers = append(blockNumbers, blockNumber)
+		}
+		blocks, err := listener.blockchain.GetBlocksByNumber(ctx, blockNumbers)
+		if err != nil {
+			log.Printf("Error retrieving the blocks missed while disconnected\n%v", err)
+			return missed
+		}
+		missed = append(missed, blocks...)
 	}
+	return missed
 }
 
-func (listener BlockchainListener) notifyObservers(block core.Block) {
+func (listener BlockchainListener) notifyObservers(ctx context.Context, block core.Block) {
 	for _, observer := range listener.observers {
-		observer.NotifyBlockAdded(block)
+		observer.NotifyBlockAdded(ctx, block)
 	}
 }
 
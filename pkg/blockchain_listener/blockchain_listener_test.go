@@ -1,6 +1,10 @@
 package blockchain_listener_test
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/vulcanize/vulcanizedb/pkg/blockchain_listener"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
@@ -14,7 +18,7 @@ var _ = Describe("Blockchain listeners", func() {
 		observer := fakes.NewFakeBlockchainObserver()
 		blockchain := fakes.NewBlockchain()
 
-		blockchain_listener.NewBlockchainListener(blockchain, []core.BlockchainObserver{observer})
+		blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
 
 		Expect(len(observer.CurrentBlocks)).To(Equal(0))
 		close(done)
@@ -23,8 +27,8 @@ var _ = Describe("Blockchain listeners", func() {
 	It("sees when one block was added", func(done Done) {
 		observer := fakes.NewFakeBlockchainObserver()
 		blockchain := fakes.NewBlockchain()
-		listener := blockchain_listener.NewBlockchainListener(blockchain, []core.BlockchainObserver{observer})
-		go listener.Start()
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		go listener.Start(context.Background())
 
 		go blockchain.AddBlock(core.Block{Number: 123})
 
@@ -39,8 +43,8 @@ var _ = Describe("Blockchain listeners", func() {
 	It("sees a second block", func(done Done) {
 		observer := fakes.NewFakeBlockchainObserver()
 		blockchain := fakes.NewBlockchain()
-		listener := blockchain_listener.NewBlockchainListener(blockchain, []core.BlockchainObserver{observer})
-		go listener.Start()
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		go listener.Start(context.Background())
 
 		go blockchain.AddBlock(core.Block{Number: 123})
 		<-observer.WasNotified
@@ -54,11 +58,26 @@ var _ = Describe("Blockchain listeners", func() {
 		close(done)
 	}, 1)
 
+	It("passes its context to the observers", func(done Done) {
+		observer := fakes.NewFakeBlockchainObserver()
+		blockchain := fakes.NewBlockchain()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		listener, _ := blockchain_listener.NewBlockchainListener(ctx, blockchain, []core.BlockchainObserver{observer})
+		go listener.Start(ctx)
+
+		go blockchain.AddBlock(core.Block{Number: 123})
+		<-observer.WasNotified
+
+		Expect(observer.Contexts[0]).To(Equal(ctx))
+		close(done)
+	}, 1)
+
 	It("stops listening", func(done Done) {
 		observer := fakes.NewFakeBlockchainObserver()
 		blockchain := fakes.NewBlockchain()
-		listener := blockchain_listener.NewBlockchainListener(blockchain, []core.BlockchainObserver{observer})
-		go listener.Start()
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		go listener.Start(context.Background())
 
 		listener.Stop()
 
@@ -66,4 +85,83 @@ var _ = Describe("Blockchain listeners", func() {
 		close(done)
 	}, 1)
 
+	It("resubscribes and notifies the observers of the blocks missed while disconnected", func(done Done) {
+		observer := fakes.NewFakeBlockchainObserver()
+		blockchain := fakes.NewBlockchainWithBlocks([]core.Block{
+			{Number: 1, Hash: "x1"},
+			{Number: 2, Hash: "x2"},
+			{Number: 3, Hash: "x3"},
+		})
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		listener.ReconnectDelay = time.Millisecond
+		go listener.Start(context.Background())
+		go blockchain.AddBlock(core.Block{Number: 1, Hash: "x1"})
+		<-observer.WasNotified
+
+		blockchain.DropSubscription(errors.New("connection lost"))
+		<-observer.WasNotified
+		<-observer.WasNotified
+
+		Expect(blockchain.SubscribeCallCount).To(Equal(2))
+		Expect(observer.CurrentBlocks[1].Hash).To(Equal("x2"))
+		Expect(observer.CurrentBlocks[2].Hash).To(Equal("x3"))
+		close(done)
+	}, 1)
+
+	It("retries the subscription with a growing delay", func(done Done) {
+		observer := fakes.NewFakeBlockchainObserver()
+		blockchain := fakes.NewBlockchain()
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		listener.ReconnectDelay = time.Millisecond
+		listener.MaxReconnectDelay = 4 * time.Millisecond
+		blockchain.FailNextSubscriptions(3)
+		go listener.Start(context.Background())
+
+		blockchain.DropSubscription(errors.New("connection lost"))
+		go blockchain.AddBlock(core.Block{Number: 123})
+		<-observer.WasNotified
+
+		Expect(blockchain.SubscribeCallCount).To(Equal(5))
+		Expect(observer.LastBlock().Number).To(Equal(int64(123)))
+		close(done)
+	}, 1)
+
+	It("does not notify the observers twice of a block seen while catching up", func(done Done) {
+		observer := fakes.NewFakeBlockchainObserver()
+		blockchain := fakes.NewBlockchainWithBlocks([]core.Block{
+			{Number: 1, Hash: "x1"},
+			{Number: 2, Hash: "x2"},
+		})
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		listener.ReconnectDelay = time.Millisecond
+		go listener.Start(context.Background())
+		go blockchain.AddBlock(core.Block{Number: 1, Hash: "x1"})
+		<-observer.WasNotified
+		blockchain.DropSubscription(errors.New("connection lost"))
+		<-observer.WasNotified
+
+		go func() {
+			blockchain.AddBlock(core.Block{Number: 2, Hash: "x2"})
+			blockchain.AddBlock(core.Block{Number: 3, Hash: "x3"})
+		}()
+		<-observer.WasNotified
+
+		Expect(len(observer.CurrentBlocks)).To(Equal(3))
+		Expect(observer.LastBlock().Hash).To(Equal("x3"))
+		close(done)
+	}, 1)
+
+	It("stops when the context is done", func(done Done) {
+		observer := fakes.NewFakeBlockchainObserver()
+		blockchain := fakes.NewBlockchain()
+		listener, _ := blockchain_listener.NewBlockchainListener(context.Background(), blockchain, []core.BlockchainObserver{observer})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := listener.Start(ctx)
+
+		Expect(err).To(Equal(context.Canceled))
+		close(done)
+	}, 1)
+
 })
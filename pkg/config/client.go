@@ -1,5 +1,32 @@
 package config
 
+import (
+	"net/url"
+	"time"
+)
+
+const defaultPollingInterval = 15 * time.Second
+
 type Client struct {
 	IPCPath string
+	// PollingInterval is the number of seconds between checks for new blocks
+	// on a node reached over HTTP, which cannot push them to subscribers.
+	PollingInterval int
+}
+
+// UsesHTTP reports whether the node is reached over HTTP rather than IPC or
+// websockets.
+func (client Client) UsesHTTP() bool {
+	parsed, err := url.Parse(client.IPCPath)
+	if err != nil {
+		return false
+	}
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
+
+func (client Client) BlockPollingInterval() time.Duration {
+	if client.PollingInterval <= 0 {
+		return defaultPollingInterval
+	}
+	return time.Duration(client.PollingInterval) * time.Second
 }
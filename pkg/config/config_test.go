@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"path/filepath"
+	"time"
 
 	cfg "github.com/vulcanize/vulcanizedb/pkg/config"
 	. "github.com/onsi/ginkgo"
@@ -36,6 +37,21 @@ var _ = Describe("Loading the config", func() {
 		Expect(infuraConfig.Database.Name).To(Equal("vulcanize_private"))
 		Expect(infuraConfig.Database.Port).To(Equal(5432))
 		Expect(infuraConfig.Client.IPCPath).To(Equal("https://mainnet.infura.io/J5Vd2fRtGsw0zZ0Ov3BL"))
+		Expect(infuraConfig.Client.UsesHTTP()).To(BeTrue())
+		Expect(infuraConfig.Client.BlockPollingInterval()).To(Equal(15 * time.Second))
+	})
+
+	It("does not poll for blocks over IPC", func() {
+		privateConfig, _ := cfg.NewConfig("private")
+
+		Expect(privateConfig.Client.UsesHTTP()).To(BeFalse())
+	})
+
+	It("defaults the block polling interval", func() {
+		client := cfg.Client{IPCPath: "http://localhost:8545"}
+
+		Expect(client.UsesHTTP()).To(BeTrue())
+		Expect(client.BlockPollingInterval()).To(Equal(15 * time.Second))
 	})
 
 })
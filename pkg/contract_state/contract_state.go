@@ -0,0 +1,61 @@
+package contract_state
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+)
+
+// RecordContractState saves the value of every attribute of the contract at
+// the given block. Attributes that cannot be read at that block are skipped.
+func RecordContractState(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, contract core.Contract, blockNumber int64) error {
+	attributes, err := blockchain.GetAttributes(ctx, contract)
+	if err != nil {
+		return err
+	}
+	var states []core.ContractState
+	for _, attribute := range attributes {
+		value, err := blockchain.GetAttribute(ctx, contract, attribute.Name, big.NewInt(blockNumber))
+		if err != nil || value == nil {
+			continue
+		}
+		states = append(states, core.ContractState{
+			ContractHash:  contract.Hash,
+			BlockNumber:   blockNumber,
+			AttributeName: attribute.Name,
+			Value:         geth.FormatAbiValue(value),
+		})
+	}
+	return repository.CreateContractState(states)
+}
+
+func RecordWatchedContractsState(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, blockNumber int64) error {
+	for _, contract := range repository.FindWatchedContracts() {
+		err := RecordContractState(ctx, blockchain, repository, contract, blockNumber)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillContractState records the state of the contract at every interval
+// blocks between the starting and ending block numbers, returning the number
+// of blocks recorded.
+func BackfillContractState(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, contract core.Contract, startingBlockNumber int64, endingBlockNumber int64, interval int64) (int, error) {
+	recorded := 0
+	for blockNumber := startingBlockNumber; blockNumber <= endingBlockNumber; blockNumber += interval {
+		if ctx.Err() != nil {
+			return recorded, ctx.Err()
+		}
+		err := RecordContractState(ctx, blockchain, repository, contract, blockNumber)
+		if err != nil {
+			return recorded, err
+		}
+		recorded++
+	}
+	return recorded, nil
+}
@@ -0,0 +1,13 @@
+package contract_state_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestContractState(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ContractState Suite")
+}
@@ -0,0 +1,78 @@
+package contract_state_test
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_state"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recording contract state", func() {
+
+	var blockchain *fakes.Blockchain
+	var repository *repositories.InMemory
+	var contract core.Contract
+
+	BeforeEach(func() {
+		blockchain = fakes.NewBlockchain()
+		repository = repositories.NewInMemory()
+		contract = core.Contract{Hash: "x123"}
+		blockchain.SetContractStateAttribute("x123", nil, "totalSupply", "")
+		blockchain.SetContractStateAttribute("x123", nil, "symbol", "")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(10), "totalSupply", "1000")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(10), "symbol", "OMG")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(20), "totalSupply", "2000")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(20), "symbol", "OMG")
+	})
+
+	It("records every attribute of the contract at the block", func() {
+		err := contract_state.RecordContractState(context.Background(), blockchain, repository, contract, 10)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(repository.FindContractState("x123", "totalSupply")).To(Equal([]core.ContractState{
+			{ContractHash: "x123", BlockNumber: 10, AttributeName: "totalSupply", Value: "1000"},
+		}))
+		Expect(repository.FindContractState("x123", "symbol")).To(Equal([]core.ContractState{
+			{ContractHash: "x123", BlockNumber: 10, AttributeName: "symbol", Value: "OMG"},
+		}))
+	})
+
+	It("records byte arrays in hex", func() {
+		blockchain.SetContractStateAttribute("x123", nil, "name", "")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(10), "name", [4]byte{0xde, 0xad, 0xbe, 0xef})
+
+		contract_state.RecordContractState(context.Background(), blockchain, repository, contract, 10)
+
+		Expect(repository.FindContractState("x123", "name")).To(Equal([]core.ContractState{
+			{ContractHash: "x123", BlockNumber: 10, AttributeName: "name", Value: "0xdeadbeef"},
+		}))
+	})
+
+	It("records the state of every watched contract", func() {
+		repository.CreateContract(contract)
+
+		contract_state.RecordWatchedContractsState(context.Background(), blockchain, repository, 20)
+
+		states := repository.FindContractState("x123", "totalSupply")
+		Expect(len(states)).To(Equal(1))
+		Expect(states[0].Value).To(Equal("2000"))
+	})
+
+	It("backfills the state at every interval blocks", func() {
+		recorded, err := contract_state.BackfillContractState(context.Background(), blockchain, repository, contract, 10, 25, 10)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(Equal(2))
+		states := repository.FindContractState("x123", "totalSupply")
+		Expect(len(states)).To(Equal(2))
+		Expect(states[0].BlockNumber).To(Equal(int64(10)))
+		Expect(states[0].Value).To(Equal("1000"))
+		Expect(states[1].BlockNumber).To(Equal(int64(20)))
+		Expect(states[1].Value).To(Equal("2000"))
+	})
+})
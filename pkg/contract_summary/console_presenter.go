@@ -1,18 +1,19 @@
 package contract_summary
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func GenerateConsoleOutput(summary ContractSummary) string {
+func GenerateConsoleOutput(ctx context.Context, summary ContractSummary) string {
 	return fmt.Sprintf(template(),
 		summary.ContractHash,
 		summary.NumberOfTransactions,
 		transactionToString(summary.LastTransaction),
-		attributesString(summary),
+		attributesString(ctx, summary),
 	)
 }
 
@@ -37,24 +38,44 @@ func transactionToString(transaction *core.Transaction) string {
 	}
 }
 
-func attributesString(summary ContractSummary) string {
+func attributesString(ctx context.Context, summary ContractSummary) string {
 	var formattedAttributes string
 	for _, attribute := range summary.Attributes {
-		formattedAttributes += formatAttribute(attribute.Name, summary) + "\n" + "                            "
+		formattedAttributes += formatAttribute(ctx, attribute.Name, summary) + "\n" + "                            "
 	}
 	return formattedAttributes
 }
 
-func formatAttribute(attributeName string, summary ContractSummary) string {
+func GenerateAttributeConsoleOutput(ctx context.Context, summary ContractSummary, attributeName string, arguments []string) (string, error) {
+	result, err := summary.GetStateAttributeWithArguments(ctx, attributeName, arguments)
+	if err != nil {
+		return "", err
+	}
+	return formatResult(attributeCallLabel(attributeName, arguments), result), nil
+}
+
+func formatAttribute(ctx context.Context, attributeName string, summary ContractSummary) string {
+	result, err := summary.GetStateAttribute(ctx, attributeName)
+	if err != nil {
+		return fmt.Sprintf("%s: error: %v", attributeName, err)
+	}
+	return formatResult(attributeName, result)
+}
+
+func formatResult(label string, result interface{}) string {
 	var stringResult string
-	result := summary.GetStateAttribute(attributeName)
 	switch t := result.(type) {
 	case common.Address:
 		ca := result.(common.Address)
-		stringResult = fmt.Sprintf("%s: %v", attributeName, ca.Hex())
+		stringResult = fmt.Sprintf("%s: %v", label, ca.Hex())
+	case core.AttributeTuple:
+		stringResult = label + ":"
+		for i, component := range t {
+			stringResult += "\n" + "                                " + formatResult(component.Label(i), component.Value)
+		}
 	default:
 		_ = t
-		stringResult = fmt.Sprintf("%s: %v", attributeName, result)
+		stringResult = fmt.Sprintf("%s: %v", label, result)
 	}
 	return stringResult
 }
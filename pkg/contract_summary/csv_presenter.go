@@ -0,0 +1,62 @@
+package contract_summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+)
+
+var csvHeader = []string{
+	"contract_hash",
+	"block_number",
+	"number_of_transactions",
+	"last_transaction_hash",
+	"last_transaction_to",
+	"last_transaction_from",
+	"attribute_name",
+	"attribute_type",
+	"attribute_value",
+	"attribute_error",
+}
+
+// GenerateCsvOutput writes one row per attribute, repeating the contract
+// columns on each row. A contract without attributes has a single row.
+func GenerateCsvOutput(ctx context.Context, summary ContractSummary) (string, error) {
+	var output bytes.Buffer
+	writer := csv.NewWriter(&output)
+	rows := [][]string{csvHeader}
+	contractColumns := csvContractColumns(summary)
+	attributes := presentAttributes(ctx, summary)
+	if len(attributes) == 0 {
+		rows = append(rows, append(contractColumns, "", "", "", ""))
+	}
+	for _, attribute := range attributes {
+		row := append([]string{}, contractColumns...)
+		rows = append(rows, append(row, attribute.Name, attribute.Type, attribute.Value, attribute.Error))
+	}
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+func csvContractColumns(summary ContractSummary) []string {
+	var blockNumber string
+	if summary.BlockNumber != nil {
+		blockNumber = summary.BlockNumber.String()
+	}
+	var hash, to, from string
+	if summary.LastTransaction != nil {
+		hash, to, from = summary.LastTransaction.Hash, summary.LastTransaction.To, summary.LastTransaction.From
+	}
+	return []string{
+		summary.ContractHash,
+		blockNumber,
+		strconv.Itoa(summary.NumberOfTransactions),
+		hash,
+		to,
+		from,
+	}
+}
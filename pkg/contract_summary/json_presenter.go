@@ -0,0 +1,96 @@
+package contract_summary
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+)
+
+type jsonSummary struct {
+	ContractHash         string               `json:"contract_hash"`
+	BlockNumber          *string              `json:"block_number"`
+	NumberOfTransactions int                  `json:"number_of_transactions"`
+	LastTransaction      *jsonTransaction     `json:"last_transaction"`
+	Attributes           []presentedAttribute `json:"attributes"`
+}
+
+type jsonTransaction struct {
+	Hash string `json:"hash"`
+	To   string `json:"to"`
+	From string `json:"from"`
+}
+
+type presentedAttribute struct {
+	Name       string               `json:"name"`
+	Type       string               `json:"type"`
+	Value      string               `json:"value"`
+	Error      string               `json:"error,omitempty"`
+	Components []presentedAttribute `json:"components,omitempty"`
+}
+
+func GenerateJsonOutput(ctx context.Context, summary ContractSummary) (string, error) {
+	output, err := json.MarshalIndent(jsonSummary{
+		ContractHash:         summary.ContractHash,
+		BlockNumber:          blockNumberString(summary),
+		NumberOfTransactions: summary.NumberOfTransactions,
+		LastTransaction:      lastJsonTransaction(summary.LastTransaction),
+		Attributes:           presentAttributes(ctx, summary),
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+func blockNumberString(summary ContractSummary) *string {
+	if summary.BlockNumber == nil {
+		return nil
+	}
+	blockNumber := summary.BlockNumber.String()
+	return &blockNumber
+}
+
+func lastJsonTransaction(transaction *core.Transaction) *jsonTransaction {
+	if transaction == nil {
+		return nil
+	}
+	return &jsonTransaction{Hash: transaction.Hash, To: transaction.To, From: transaction.From}
+}
+
+func presentAttributes(ctx context.Context, summary ContractSummary) []presentedAttribute {
+	presentedAttributes := []presentedAttribute{}
+	for _, attribute := range summary.Attributes {
+		value, err := summary.GetStateAttribute(ctx, attribute.Name)
+		if err != nil {
+			presentedAttributes = append(presentedAttributes,
+				presentedAttribute{Name: attribute.Name, Type: attribute.Type, Error: err.Error()})
+			continue
+		}
+		presentedAttributes = append(presentedAttributes, presentAttribute(attribute.Name, attribute.Type, value))
+	}
+	// the abi types of called methods are not part of the summary
+	for _, attribute := range summary.calledAttributes {
+		presentedAttributes = append(presentedAttributes, presentAttribute(attribute.label, "", attribute.value))
+	}
+	return presentedAttributes
+}
+
+func presentAttribute(name string, attributeType string, value interface{}) presentedAttribute {
+	attribute := presentedAttribute{Name: name, Type: attributeType, Value: formatValue(value)}
+	if tuple, ok := value.(core.AttributeTuple); ok {
+		for i, component := range tuple {
+			attribute.Components = append(attribute.Components,
+				presentAttribute(component.Label(i), component.Type, component.Value))
+		}
+	}
+	return attribute
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return geth.FormatAbiValue(value)
+}
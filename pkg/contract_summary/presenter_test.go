@@ -0,0 +1,167 @@
+package contract_summary_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_summary"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Presenting a contract summary", func() {
+	var repository *repositories.InMemory
+	var blockchain *fakes.Blockchain
+
+	BeforeEach(func() {
+		repository = repositories.NewInMemory()
+		repository.CreateContract(core.Contract{Hash: "0x123"})
+		repository.CreateOrUpdateBlock(core.Block{
+			Number: 1,
+			Transactions: []core.Transaction{
+				{Hash: "TRANSACTION1", To: "0x123", From: "0x456"},
+			},
+		})
+		blockchain = fakes.NewBlockchain()
+	})
+
+	Describe("as JSON", func() {
+		It("includes the contract, its last transaction and its attributes", func() {
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+			blockchain.SetContractStateAttribute("0x123", nil, "baz", "qux")
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+
+			output, err := contract_summary.GenerateJsonOutput(context.Background(), contractSummary)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{
+				"contract_hash": "0x123",
+				"block_number": null,
+				"number_of_transactions": 1,
+				"last_transaction": {"hash": "TRANSACTION1", "to": "0x123", "from": "0x456"},
+				"attributes": [
+					{"name": "baz", "type": "string", "value": "qux"},
+					{"name": "foo", "type": "string", "value": "bar"}
+				]
+			}`))
+		})
+
+		It("includes the requested block number", func() {
+			blockNumber := big.NewInt(1000)
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+			blockchain.SetContractStateAttribute("0x123", blockNumber, "foo", "baz")
+			contractSummary, _ := contract_summary.NewSummary(context.Background(), blockchain, repository, "0x123", blockNumber)
+
+			output, _ := contract_summary.GenerateJsonOutput(context.Background(), contractSummary)
+
+			var decoded map[string]interface{}
+			json.Unmarshal([]byte(output), &decoded)
+			Expect(decoded["block_number"]).To(Equal("1000"))
+			Expect(decoded["attributes"]).To(Equal([]interface{}{
+				map[string]interface{}{"name": "foo", "type": "string", "value": "baz"},
+			}))
+		})
+
+		It("presents a missing last transaction and attributes as null and empty", func() {
+			repository.CreateContract(core.Contract{Hash: "0x789"})
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x789")
+
+			output, _ := contract_summary.GenerateJsonOutput(context.Background(), contractSummary)
+
+			Expect(output).To(MatchJSON(`{
+				"contract_hash": "0x789",
+				"block_number": null,
+				"number_of_transactions": 0,
+				"last_transaction": null,
+				"attributes": []
+			}`))
+		})
+
+		It("includes called attributes after the attributes, with byte arrays in hex", func() {
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+			blockchain.SetContractStateAttributeWithArguments("0x123", nil, "nameOf", []string{"0x456", "1"}, [4]byte{0xde, 0xad, 0xbe, 0xef})
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+			contractSummary, err := contractSummary.CallAttribute(context.Background(), "nameOf", []string{"0x456", "1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			output, _ := contract_summary.GenerateJsonOutput(context.Background(), contractSummary)
+
+			var decoded map[string]interface{}
+			json.Unmarshal([]byte(output), &decoded)
+			Expect(decoded["attributes"]).To(Equal([]interface{}{
+				map[string]interface{}{"name": "foo", "type": "string", "value": "bar"},
+				map[string]interface{}{"name": "nameOf(0x456, 1)", "type": "", "value": "0xdeadbeef"},
+			}))
+		})
+
+		It("includes the error of an attribute whose call failed", func() {
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+			blockchain.SetAttributeError("foo", errors.New("execution reverted"))
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+
+			output, _ := contract_summary.GenerateJsonOutput(context.Background(), contractSummary)
+
+			var decoded map[string]interface{}
+			json.Unmarshal([]byte(output), &decoded)
+			Expect(decoded["attributes"]).To(Equal([]interface{}{
+				map[string]interface{}{"name": "foo", "type": "string", "value": "", "error": "execution reverted"},
+			}))
+		})
+	})
+
+	Describe("as CSV", func() {
+		It("writes a row for each attribute", func() {
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+			blockchain.SetContractStateAttribute("0x123", nil, "baz", "qux, quux")
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+
+			output, err := contract_summary.GenerateCsvOutput(context.Background(), contractSummary)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal(
+				"contract_hash,block_number,number_of_transactions,last_transaction_hash,last_transaction_to,last_transaction_from,attribute_name,attribute_type,attribute_value,attribute_error\n" +
+					"0x123,,1,TRANSACTION1,0x123,0x456,baz,string,\"qux, quux\",\n" +
+					"0x123,,1,TRANSACTION1,0x123,0x456,foo,string,bar,\n"))
+		})
+
+		It("writes a single row for a contract without attributes", func() {
+			repository.CreateContract(core.Contract{Hash: "0x789"})
+			contractSummary, _ := contract_summary.NewSummary(context.Background(), blockchain, repository, "0x789", big.NewInt(1000))
+
+			output, _ := contract_summary.GenerateCsvOutput(context.Background(), contractSummary)
+
+			Expect(output).To(Equal(
+				"contract_hash,block_number,number_of_transactions,last_transaction_hash,last_transaction_to,last_transaction_from,attribute_name,attribute_type,attribute_value,attribute_error\n" +
+					"0x789,1000,0,,,,,,,\n"))
+		})
+
+		It("writes the error of an attribute whose call failed", func() {
+			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
+			blockchain.SetAttributeError("foo", errors.New("execution reverted"))
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+
+			output, _ := contract_summary.GenerateCsvOutput(context.Background(), contractSummary)
+
+			Expect(output).To(Equal(
+				"contract_hash,block_number,number_of_transactions,last_transaction_hash,last_transaction_to,last_transaction_from,attribute_name,attribute_type,attribute_value,attribute_error\n" +
+					"0x123,,1,TRANSACTION1,0x123,0x456,foo,string,,execution reverted\n"))
+		})
+
+		It("writes a row for each called attribute", func() {
+			blockchain.SetContractStateAttributeWithArguments("0x123", nil, "balanceOf", []string{"0x456"}, "100")
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+			contractSummary, _ = contractSummary.CallAttribute(context.Background(), "balanceOf", []string{"0x456"})
+
+			output, _ := contract_summary.GenerateCsvOutput(context.Background(), contractSummary)
+
+			Expect(output).To(Equal(
+				"contract_hash,block_number,number_of_transactions,last_transaction_hash,last_transaction_to,last_transaction_from,attribute_name,attribute_type,attribute_value,attribute_error\n" +
+					"0x123,,1,TRANSACTION1,0x123,0x456,balanceOf(0x456),,100,\n"))
+		})
+	})
+})
@@ -1,7 +1,10 @@
 package contract_summary
 
 import (
+	"context"
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/repositories"
@@ -15,25 +18,57 @@ type ContractSummary struct {
 	LastTransaction      *core.Transaction
 	NumberOfTransactions int
 	blockChain           core.Blockchain
+	calledAttributes     []calledAttribute
 }
 
-func NewSummary(blockchain core.Blockchain, repository repositories.Repository, contractHash string, blockNumber *big.Int) (ContractSummary, error) {
+func NewSummary(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, contractHash string, blockNumber *big.Int) (ContractSummary, error) {
 	contract, err := repository.FindContract(contractHash)
 	if err != nil {
 		return ContractSummary{}, err
 	} else {
-		return newContractSummary(blockchain, contract, blockNumber), nil
+		return newContractSummary(ctx, blockchain, contract, blockNumber)
 	}
 }
 
-func (contractSummary ContractSummary) GetStateAttribute(attributeName string) interface{} {
-	var result interface{}
-	result, _ = contractSummary.blockChain.GetAttribute(contractSummary.Contract, attributeName, contractSummary.BlockNumber)
-	return result
+func (contractSummary ContractSummary) GetStateAttribute(ctx context.Context, attributeName string) (interface{}, error) {
+	return contractSummary.blockChain.GetAttribute(ctx, contractSummary.Contract, attributeName, contractSummary.BlockNumber)
 }
 
-func newContractSummary(blockchain core.Blockchain, contract core.Contract, blockNumber *big.Int) ContractSummary {
-	attributes, _ := blockchain.GetAttributes(contract)
+func (contractSummary ContractSummary) GetStateAttributeWithArguments(ctx context.Context, attributeName string, arguments []string) (interface{}, error) {
+	return contractSummary.blockChain.GetAttributeWithArguments(ctx, contractSummary.Contract, attributeName, arguments, contractSummary.BlockNumber)
+}
+
+// calledAttribute is the result of a constant method called with arguments,
+// e.g. balanceOf(0x123), which the presenters list after the attributes.
+type calledAttribute struct {
+	label string
+	value interface{}
+}
+
+// CallAttribute returns a copy of the summary that includes the result of
+// calling the method with the arguments.
+func (contractSummary ContractSummary) CallAttribute(ctx context.Context, attributeName string, arguments []string) (ContractSummary, error) {
+	result, err := contractSummary.GetStateAttributeWithArguments(ctx, attributeName, arguments)
+	if err != nil {
+		return contractSummary, err
+	}
+	calledAttributes := append([]calledAttribute{}, contractSummary.calledAttributes...)
+	contractSummary.calledAttributes = append(calledAttributes, calledAttribute{
+		label: attributeCallLabel(attributeName, arguments),
+		value: result,
+	})
+	return contractSummary, nil
+}
+
+func attributeCallLabel(attributeName string, arguments []string) string {
+	return fmt.Sprintf("%s(%s)", attributeName, strings.Join(arguments, ", "))
+}
+
+func newContractSummary(ctx context.Context, blockchain core.Blockchain, contract core.Contract, blockNumber *big.Int) (ContractSummary, error) {
+	attributes, err := blockchain.GetAttributes(ctx, contract)
+	if err != nil {
+		return ContractSummary{}, err
+	}
 	return ContractSummary{
 		Attributes:           attributes,
 		BlockNumber:          blockNumber,
@@ -42,7 +77,7 @@ func newContractSummary(blockchain core.Blockchain, contract core.Contract, bloc
 		LastTransaction:      lastTransaction(contract),
 		NumberOfTransactions: len(contract.Transactions),
 		blockChain:           blockchain,
-	}
+	}, nil
 }
 
 func lastTransaction(contract core.Contract) *core.Transaction {
@@ -1,6 +1,8 @@
 package contract_summary_test
 
 import (
+	"context"
+	"errors"
 	"math/big"
 
 	"github.com/vulcanize/vulcanizedb/pkg/contract_summary"
@@ -12,7 +14,7 @@ import (
 )
 
 func NewCurrentContractSummary(blockchain core.Blockchain, repository repositories.Repository, contractHash string) (contract_summary.ContractSummary, error) {
-	return contract_summary.NewSummary(blockchain, repository, contractHash, nil)
+	return contract_summary.NewSummary(context.Background(), blockchain, repository, contractHash, nil)
 }
 
 var _ = Describe("The contract summary", func() {
@@ -97,7 +99,7 @@ var _ = Describe("The contract summary", func() {
 			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
 
 			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
-			attribute := contractSummary.GetStateAttribute("foo")
+			attribute, _ := contractSummary.GetStateAttribute(context.Background(), "foo")
 
 			Expect(attribute).To(Equal("bar"))
 		})
@@ -111,12 +113,42 @@ var _ = Describe("The contract summary", func() {
 			blockchain.SetContractStateAttribute("0x123", nil, "foo", "bar")
 			blockchain.SetContractStateAttribute("0x123", blockNumber, "foo", "baz")
 
-			contractSummary, _ := contract_summary.NewSummary(blockchain, repository, "0x123", blockNumber)
-			attribute := contractSummary.GetStateAttribute("foo")
+			contractSummary, _ := contract_summary.NewSummary(context.Background(), blockchain, repository, "0x123", blockNumber)
+			attribute, _ := contractSummary.GetStateAttribute(context.Background(), "foo")
 
 			Expect(attribute).To(Equal("baz"))
 		})
 
+		It("gets contract state attribute that takes arguments from the blockchain", func() {
+			repository := repositories.NewInMemory()
+			contract := core.Contract{Hash: "0x123"}
+			repository.CreateContract(contract)
+			blockchain := fakes.NewBlockchain()
+			blockchain.SetContractStateAttributeWithArguments("0x123", nil, "balanceOf", []string{"0x456"}, "100")
+			blockchain.SetContractStateAttributeWithArguments("0x123", nil, "balanceOf", []string{"0x789"}, "200")
+
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+			attribute, err := contractSummary.GetStateAttributeWithArguments(context.Background(), "balanceOf", []string{"0x789"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attribute).To(Equal("200"))
+			output, err := contract_summary.GenerateAttributeConsoleOutput(context.Background(), contractSummary, "balanceOf", []string{"0x456"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("balanceOf(0x456): 100"))
+		})
+
+		It("returns the error of an attribute that cannot be read with the arguments", func() {
+			repository := repositories.NewInMemory()
+			repository.CreateContract(core.Contract{Hash: "0x123"})
+			blockchain := fakes.NewBlockchain()
+			blockchain.SetAttributeError("balanceOf", errors.New("invalid argument"))
+
+			contractSummary, _ := NewCurrentContractSummary(blockchain, repository, "0x123")
+			_, err := contract_summary.GenerateAttributeConsoleOutput(context.Background(), contractSummary, "balanceOf", []string{"not an address"})
+
+			Expect(err).To(MatchError("invalid argument"))
+		})
+
 		It("gets attributes for the contract from the blockchain", func() {
 			repository := repositories.NewInMemory()
 			contract := core.Contract{Hash: "0x123"}
@@ -1,7 +1,9 @@
 package core
 
+import "math/big"
+
 type Block struct {
-	Difficulty   int64
+	Difficulty   *big.Int
 	GasLimit     int64
 	GasUsed      int64
 	Hash         string
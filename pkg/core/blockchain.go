@@ -1,15 +1,24 @@
 package core
 
-import "math/big"
+import (
+	"context"
+	"math/big"
+)
 
+// Blockchain is the node the data is read from. Every method that makes a
+// request to the node takes a context to bound it and returns the error when
+// the request fails.
 type Blockchain interface {
-	GetBlockByNumber(blockNumber int64) Block
-	LastBlock() *big.Int
+	GetBlockByNumber(ctx context.Context, blockNumber int64) (Block, error)
+	GetBlocksByNumber(ctx context.Context, blockNumbers []int64) ([]Block, error)
+	LastBlock(ctx context.Context) (*big.Int, error)
 	Node() Node
-	SubscribeToBlocks(blocks chan Block)
-	StartListening()
+	SubscribeToBlocks(ctx context.Context, blocks chan Block) error
+	StartListening(ctx context.Context) error
 	StopListening()
-	GetAttributes(contract Contract) (ContractAttributes, error)
-	GetAttribute(contract Contract, attributeName string, blockNumber *big.Int) (interface{}, error)
-	GetLogs(contract Contract, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ([]Log, error)
+	GetAttributes(ctx context.Context, contract Contract) (ContractAttributes, error)
+	GetAttribute(ctx context.Context, contract Contract, attributeName string, blockNumber *big.Int) (interface{}, error)
+	GetAttributeWithArguments(ctx context.Context, contract Contract, attributeName string, arguments []string, blockNumber *big.Int) (interface{}, error)
+	GetLogs(ctx context.Context, contract Contract, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ([]Log, error)
+	FilterLogs(ctx context.Context, filter LogFilter, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ([]Log, error)
 }
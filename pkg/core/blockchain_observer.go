@@ -1,5 +1,7 @@
 package core
 
+import "context"
+
 type BlockchainObserver interface {
-	NotifyBlockAdded(Block)
+	NotifyBlockAdded(context.Context, Block)
 }
@@ -1,7 +1,10 @@
 package core
 
 type Contract struct {
-	Abi          string
-	Hash         string
-	Transactions []Transaction
+	Abi  string
+	Hash string
+	// DeploymentBlock is the number of the block the contract was created in,
+	// or 0 when it is not known.
+	DeploymentBlock int64
+	Transactions    []Transaction
 }
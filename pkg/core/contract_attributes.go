@@ -1,10 +1,22 @@
 package core
 
-type ContractAttribute struct {
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type AttributeOutput struct {
 	Name string
 	Type string
 }
 
+type ContractAttribute struct {
+	Name    string
+	Type    string
+	Outputs []AttributeOutput
+}
+
 type ContractAttributes []ContractAttribute
 
 func (attributes ContractAttributes) Len() int {
@@ -18,3 +30,29 @@ func (attributes ContractAttributes) Swap(i, j int) {
 func (attributes ContractAttributes) Less(i, j int) bool {
 	return attributes[i].Name < attributes[j].Name
 }
+
+type AttributeComponent struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// AttributeTuple is the result of an attribute with more than one output,
+// holding one component per output in the order they are returned.
+type AttributeTuple []AttributeComponent
+
+func (tuple AttributeTuple) String() string {
+	var components []string
+	for i, component := range tuple {
+		components = append(components, fmt.Sprintf("%s: %v", component.Label(i), component.Value))
+	}
+	return strings.Join(components, ", ")
+}
+
+// Label is the name of the component, or its position when the output is unnamed.
+func (component AttributeComponent) Label(position int) string {
+	if component.Name == "" {
+		return strconv.Itoa(position)
+	}
+	return component.Name
+}
@@ -0,0 +1,28 @@
+package core_test
+
+import (
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Attribute tuples", func() {
+
+	It("formats each component with its name", func() {
+		tuple := core.AttributeTuple{
+			{Name: "reserve0", Type: "uint112", Value: 100},
+			{Name: "reserve1", Type: "uint112", Value: 200},
+		}
+
+		Expect(tuple.String()).To(Equal("reserve0: 100, reserve1: 200"))
+	})
+
+	It("labels unnamed components by their position", func() {
+		tuple := core.AttributeTuple{
+			{Name: "reserve0", Type: "uint112", Value: 100},
+			{Name: "", Type: "uint32", Value: 1514764800},
+		}
+
+		Expect(tuple.String()).To(Equal("reserve0: 100, 1: 1514764800"))
+	})
+})
@@ -0,0 +1,23 @@
+package core
+
+// ContractCreation is a transaction that deployed a contract, with the hash
+// of the runtime code left at the created address.
+type ContractCreation struct {
+	BlockNumber     int64
+	TxHash          string
+	CreatorAddress  string
+	ContractAddress string
+	CodeHash        string
+}
+
+// NewContractCreation reads the creation from a transaction that
+// CreatesContract.
+func NewContractCreation(blockNumber int64, transaction Transaction) ContractCreation {
+	return ContractCreation{
+		BlockNumber:     blockNumber,
+		TxHash:          transaction.Hash,
+		CreatorAddress:  transaction.From,
+		ContractAddress: transaction.Receipt.ContractAddress,
+		CodeHash:        transaction.Receipt.ContractCodeHash,
+	}
+}
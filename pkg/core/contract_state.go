@@ -0,0 +1,8 @@
+package core
+
+type ContractState struct {
+	ContractHash  string
+	BlockNumber   int64
+	AttributeName string
+	Value         string
+}
@@ -0,0 +1,15 @@
+package core
+
+type CallArgument struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type DecodedCall struct {
+	TxHash    string
+	To        string
+	From      string
+	Method    string
+	Arguments []CallArgument
+}
@@ -0,0 +1,17 @@
+package core
+
+type EventArgument struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+	Value   string `json:"value"`
+}
+
+type DecodedEvent struct {
+	Address     string
+	BlockNumber int64
+	LogIndex    int64
+	TxHash      string
+	Name        string
+	Arguments   []EventArgument
+}
@@ -0,0 +1,18 @@
+package core
+
+type EventTableColumn struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+}
+
+// EventTableLogColumns are the columns every event table has for the log of
+// the event, ahead of the columns of its arguments.
+var EventTableLogColumns = []string{"log_id", "block_number", "log_index", "tx_hash"}
+
+type EventTable struct {
+	Name         string
+	ContractHash string
+	EventName    string
+	Columns      []EventTableColumn
+}
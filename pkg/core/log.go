@@ -8,3 +8,9 @@ type Log struct {
 	Index       int64
 	Data        string
 }
+
+// LogPosition orders logs by block, then by index within the block.
+type LogPosition struct {
+	BlockNumber int64
+	Index       int64
+}
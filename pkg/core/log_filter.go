@@ -0,0 +1,35 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+// LogFilter selects logs by the contracts that emitted them and their
+// topics. Without addresses, logs from every contract match. Topics lists
+// the accepted values at each topic position, where no values match any
+// topic, so {{transferSignature}, {}, {recipient}} selects the transfers to
+// the recipient.
+type LogFilter struct {
+	Addresses []string
+	Topics    [][]string
+}
+
+// TopicsKey identifies the logs selected by the topics, whatever the case
+// and order of the values, so that {{a, b}, {}} and {{B, A}} share a key.
+// It is empty when every topic matches.
+func (filter LogFilter) TopicsKey() string {
+	var positions []string
+	for _, values := range filter.Topics {
+		var position []string
+		for _, value := range values {
+			position = append(position, strings.ToLower(value))
+		}
+		sort.Strings(position)
+		positions = append(positions, strings.Join(position, ","))
+	}
+	for len(positions) > 0 && positions[len(positions)-1] == "" {
+		positions = positions[:len(positions)-1]
+	}
+	return strings.Join(positions, ";")
+}
@@ -0,0 +1,29 @@
+package core_test
+
+import (
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Log filter topics key", func() {
+
+	It("is empty when every topic matches", func() {
+		Expect(core.LogFilter{}.TopicsKey()).To(BeEmpty())
+		Expect(core.LogFilter{Topics: [][]string{{}, {}}}.TopicsKey()).To(BeEmpty())
+	})
+
+	It("does not depend on the case and order of the values", func() {
+		filter := core.LogFilter{Topics: [][]string{{"0xAB", "0xcd"}, {}}}
+		same := core.LogFilter{Topics: [][]string{{"0xcd", "0xab"}}}
+
+		Expect(filter.TopicsKey()).To(Equal(same.TopicsKey()))
+	})
+
+	It("differs for values at other positions", func() {
+		filter := core.LogFilter{Topics: [][]string{{"0xab"}}}
+		other := core.LogFilter{Topics: [][]string{{}, {"0xab"}}}
+
+		Expect(filter.TopicsKey()).NotTo(Equal(other.TopicsKey()))
+	})
+})
@@ -0,0 +1,10 @@
+package core
+
+// LogRange is an inclusive range of blocks scanned for a contract's logs
+// matching the topics identified by TopicsKey, as from LogFilter.TopicsKey.
+type LogRange struct {
+	ContractHash        string
+	TopicsKey           string
+	StartingBlockNumber int64
+	EndingBlockNumber   int64
+}
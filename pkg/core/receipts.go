@@ -0,0 +1,13 @@
+package core
+
+type Receipt struct {
+	Bloom             string
+	ContractAddress   string
+	ContractCodeHash  string
+	CumulativeGasUsed int64
+	GasUsed           int64
+	Logs              []Log
+	StateRoot         string
+	Status            int
+	TxHash            string
+}
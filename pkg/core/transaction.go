@@ -1,5 +1,7 @@
 package core
 
+import "math/big"
+
 type Transaction struct {
 	Hash     string
 	Data     []byte
@@ -7,6 +9,13 @@ type Transaction struct {
 	To       string
 	From     string
 	GasLimit int64
-	GasPrice int64
-	Value    int64
+	GasPrice *big.Int
+	Value    *big.Int
+	Receipt  Receipt
+}
+
+// CreatesContract is true for a successful transaction that deployed a
+// contract. The hash of the contract's runtime code is set on the receipt.
+func (transaction Transaction) CreatesContract() bool {
+	return transaction.To == "" && transaction.Receipt.ContractAddress != "" && transaction.Receipt.Status != 0
 }
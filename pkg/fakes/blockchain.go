@@ -1,7 +1,11 @@
 package fakes
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sort"
+	"strings"
 
 	"math/big"
 
@@ -10,32 +14,111 @@ import (
 
 type Blockchain struct {
 	logs               map[string][]core.Log
+	logErrors          map[int64]error
 	blocks             map[int64]core.Block
-	contractAttributes map[string]map[string]string
+	contractAttributes map[string]map[string]interface{}
+	attributeArguments map[string]interface{}
+	attributeErrors    map[string]error
 	blocksChannel      chan core.Block
+	blockErrors        map[int64]error
+	lastBlockError     error
+	subscriptionErrors chan error
+	subscribeFailures  int
+	SubscribeCallCount int
 	WasToldToStop      bool
 	node               core.Node
 }
 
-func (blockchain *Blockchain) LastBlock() *big.Int {
+func (blockchain *Blockchain) LastBlock(ctx context.Context) (*big.Int, error) {
+	if blockchain.lastBlockError != nil {
+		return nil, blockchain.lastBlockError
+	}
 	var max int64
 	for blockNumber := range blockchain.blocks {
 		if blockNumber > max {
 			max = blockNumber
 		}
 	}
-	return big.NewInt(max)
+	return big.NewInt(max), nil
+}
+
+func (blockchain *Blockchain) SetLastBlockError(err error) {
+	blockchain.lastBlockError = err
 }
 
-func (blockchain *Blockchain) GetLogs(contract core.Contract, startingBlock *big.Int, endingBlock *big.Int) ([]core.Log, error) {
-	return blockchain.logs[contract.Hash], nil
+func (blockchain *Blockchain) GetLogs(ctx context.Context, contract core.Contract, startingBlock *big.Int, endingBlock *big.Int) ([]core.Log, error) {
+	return blockchain.FilterLogs(ctx, core.LogFilter{Addresses: []string{contract.Hash}}, startingBlock, endingBlock)
+}
+
+func (blockchain *Blockchain) FilterLogs(ctx context.Context, filter core.LogFilter, startingBlock *big.Int, endingBlock *big.Int) ([]core.Log, error) {
+	if err, ok := blockchain.logErrors[startingBlock.Int64()]; ok {
+		return nil, err
+	}
+	var logs []core.Log
+	for contractHash, contractLogs := range blockchain.logs {
+		if len(filter.Addresses) > 0 && !containsFold(filter.Addresses, contractHash) {
+			continue
+		}
+		for _, log := range contractLogs {
+			if log.BlockNumber >= startingBlock.Int64() && log.BlockNumber <= endingBlock.Int64() && matchesTopics(filter.Topics, log) {
+				logs = append(logs, log)
+			}
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+	return logs, nil
+}
+
+func matchesTopics(topics [][]string, log core.Log) bool {
+	for position, values := range topics {
+		if len(values) > 0 && !containsFold(values, log.Topics[position]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (blockchain *Blockchain) SetLogs(contractHash string, logs []core.Log) {
+	if blockchain.logs == nil {
+		blockchain.logs = make(map[string][]core.Log)
+	}
+	blockchain.logs[contractHash] = logs
+}
+
+// SetLogsError makes requests for logs starting at the block fail with err.
+func (blockchain *Blockchain) SetLogsError(startingBlockNumber int64, err error) {
+	if blockchain.logErrors == nil {
+		blockchain.logErrors = make(map[int64]error)
+	}
+	blockchain.logErrors[startingBlockNumber] = err
+}
+
+func (blockchain *Blockchain) ClearLogsError(startingBlockNumber int64) {
+	delete(blockchain.logErrors, startingBlockNumber)
 }
 
 func (blockchain *Blockchain) Node() core.Node {
 	return blockchain.node
 }
 
-func (blockchain *Blockchain) GetAttribute(contract core.Contract, attributeName string, blockNumber *big.Int) (interface{}, error) {
+func (blockchain *Blockchain) GetAttribute(ctx context.Context, contract core.Contract, attributeName string, blockNumber *big.Int) (interface{}, error) {
+	if err, ok := blockchain.attributeErrors[attributeName]; ok {
+		return nil, err
+	}
 	var result interface{}
 	if blockNumber == nil {
 		result = blockchain.contractAttributes[contract.Hash+"-1"][attributeName]
@@ -45,11 +128,25 @@ func (blockchain *Blockchain) GetAttribute(contract core.Contract, attributeName
 	return result, nil
 }
 
+func (blockchain *Blockchain) GetAttributeWithArguments(ctx context.Context, contract core.Contract, attributeName string, arguments []string, blockNumber *big.Int) (interface{}, error) {
+	if err, ok := blockchain.attributeErrors[attributeName]; ok {
+		return nil, err
+	}
+	if len(arguments) == 0 {
+		return blockchain.GetAttribute(ctx, contract, attributeName, blockNumber)
+	}
+	var result interface{}
+	result = blockchain.attributeArguments[attributeArgumentsKey(contract.Hash, blockNumber, attributeName, arguments)]
+	return result, nil
+}
+
 func NewBlockchain() *Blockchain {
 	return &Blockchain{
 		blocks:             make(map[int64]core.Block),
 		logs:               make(map[string][]core.Log),
-		contractAttributes: make(map[string]map[string]string),
+		contractAttributes: make(map[string]map[string]interface{}),
+		attributeArguments: make(map[string]interface{}),
+		subscriptionErrors: make(chan error),
 		node:               core.Node{GenesisBlock: "GENESIS"},
 	}
 }
@@ -60,16 +157,51 @@ func NewBlockchainWithBlocks(blocks []core.Block) *Blockchain {
 		blockNumberToBlocks[block.Number] = block
 	}
 	return &Blockchain{
-		blocks: blockNumberToBlocks,
+		blocks:             blockNumberToBlocks,
+		subscriptionErrors: make(chan error),
 	}
 }
 
-func (blockchain *Blockchain) GetBlockByNumber(blockNumber int64) core.Block {
-	return blockchain.blocks[blockNumber]
+func (blockchain *Blockchain) GetBlockByNumber(ctx context.Context, blockNumber int64) (core.Block, error) {
+	if err, ok := blockchain.blockErrors[blockNumber]; ok {
+		return core.Block{}, err
+	}
+	return blockchain.blocks[blockNumber], nil
 }
 
-func (blockchain *Blockchain) SubscribeToBlocks(outputBlocks chan core.Block) {
+func (blockchain *Blockchain) GetBlocksByNumber(ctx context.Context, blockNumbers []int64) ([]core.Block, error) {
+	var blocks []core.Block
+	for _, blockNumber := range blockNumbers {
+		block, err := blockchain.GetBlockByNumber(ctx, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// SetBlockError makes requests for the block fail with err.
+func (blockchain *Blockchain) SetBlockError(blockNumber int64, err error) {
+	if blockchain.blockErrors == nil {
+		blockchain.blockErrors = make(map[int64]error)
+	}
+	blockchain.blockErrors[blockNumber] = err
+}
+
+func (blockchain *Blockchain) SubscribeToBlocks(ctx context.Context, outputBlocks chan core.Block) error {
+	blockchain.SubscribeCallCount++
+	if blockchain.subscribeFailures > 0 {
+		blockchain.subscribeFailures--
+		return errors.New("subscription failed")
+	}
 	blockchain.blocksChannel = outputBlocks
+	return nil
+}
+
+// FailNextSubscriptions makes the next count calls to SubscribeToBlocks fail.
+func (blockchain *Blockchain) FailNextSubscriptions(count int) {
+	blockchain.subscribeFailures = count
 }
 
 func (blockchain *Blockchain) AddBlock(block core.Block) {
@@ -77,13 +209,28 @@ func (blockchain *Blockchain) AddBlock(block core.Block) {
 	blockchain.blocksChannel <- block
 }
 
-func (*Blockchain) StartListening() {}
+// StartListening waits until the subscription is dropped with
+// DropSubscription or the context is done.
+func (blockchain *Blockchain) StartListening(ctx context.Context) error {
+	select {
+	case err := <-blockchain.subscriptionErrors:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DropSubscription makes StartListening return err, as when the connection
+// to the node is lost.
+func (blockchain *Blockchain) DropSubscription(err error) {
+	blockchain.subscriptionErrors <- err
+}
 
 func (blockchain *Blockchain) StopListening() {
 	blockchain.WasToldToStop = true
 }
 
-func (blockchain *Blockchain) SetContractStateAttribute(contractHash string, blockNumber *big.Int, attributeName string, attributeValue string) {
+func (blockchain *Blockchain) SetContractStateAttribute(contractHash string, blockNumber *big.Int, attributeName string, attributeValue interface{}) {
 	var key string
 	if blockNumber == nil {
 		key = contractHash + "-1"
@@ -92,12 +239,35 @@ func (blockchain *Blockchain) SetContractStateAttribute(contractHash string, blo
 	}
 	contractStateAttributes := blockchain.contractAttributes[key]
 	if contractStateAttributes == nil {
-		blockchain.contractAttributes[key] = make(map[string]string)
+		blockchain.contractAttributes[key] = make(map[string]interface{})
 	}
 	blockchain.contractAttributes[key][attributeName] = attributeValue
 }
 
-func (blockchain *Blockchain) GetAttributes(contract core.Contract) (core.ContractAttributes, error) {
+func (blockchain *Blockchain) SetContractStateAttributeWithArguments(contractHash string, blockNumber *big.Int, attributeName string, arguments []string, attributeValue interface{}) {
+	if blockchain.attributeArguments == nil {
+		blockchain.attributeArguments = make(map[string]interface{})
+	}
+	blockchain.attributeArguments[attributeArgumentsKey(contractHash, blockNumber, attributeName, arguments)] = attributeValue
+}
+
+// SetAttributeError makes calls to the attribute fail with err.
+func (blockchain *Blockchain) SetAttributeError(attributeName string, err error) {
+	if blockchain.attributeErrors == nil {
+		blockchain.attributeErrors = make(map[string]error)
+	}
+	blockchain.attributeErrors[attributeName] = err
+}
+
+func attributeArgumentsKey(contractHash string, blockNumber *big.Int, attributeName string, arguments []string) string {
+	key := contractHash + "-1"
+	if blockNumber != nil {
+		key = contractHash + blockNumber.String()
+	}
+	return fmt.Sprintf("%s-%s(%s)", key, attributeName, strings.Join(arguments, ","))
+}
+
+func (blockchain *Blockchain) GetAttributes(ctx context.Context, contract core.Contract) (core.ContractAttributes, error) {
 	var contractAttributes core.ContractAttributes
 	attributes, ok := blockchain.contractAttributes[contract.Hash+"-1"]
 	if ok {
@@ -1,9 +1,14 @@
 package fakes
 
-import "github.com/vulcanize/vulcanizedb/pkg/core"
+import (
+	"context"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
 
 type BlockchainObserver struct {
 	CurrentBlocks []core.Block
+	Contexts      []context.Context
 	WasNotified   chan bool
 }
 
@@ -17,7 +22,8 @@ func NewFakeBlockchainObserver() *BlockchainObserver {
 	}
 }
 
-func (observer *BlockchainObserver) NotifyBlockAdded(block core.Block) {
+func (observer *BlockchainObserver) NotifyBlockAdded(ctx context.Context, block core.Block) {
 	observer.CurrentBlocks = append(observer.CurrentBlocks, block)
+	observer.Contexts = append(observer.Contexts, ctx)
 	observer.WasNotified <- true
 }
@@ -0,0 +1,148 @@
+package geth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const unpackMethodName = "unpack"
+
+var (
+	ErrInvalidArgument         = errors.New("invalid argument")
+	ErrUnsupportedArgumentType = errors.New("unsupported argument type")
+)
+
+// unpackArguments decodes ABI encoded output into one value per argument,
+// going through a method on a throwaway ABI so that any list of arguments
+// (e.g. the non-indexed inputs of an event) can be unpacked.
+func unpackArguments(arguments []abi.Argument, output []byte) ([]interface{}, error) {
+	if len(arguments) == 0 {
+		return []interface{}{}, nil
+	}
+	method := abi.Method{Name: unpackMethodName, Const: true, Outputs: arguments}
+	parsed := abi.ABI{Methods: map[string]abi.Method{unpackMethodName: method}}
+	if len(arguments) == 1 {
+		var result interface{}
+		err := parsed.Unpack(&result, unpackMethodName, output)
+		return []interface{}{result}, err
+	}
+	results := make([]interface{}, len(arguments))
+	for i := range results {
+		results[i] = new(interface{})
+	}
+	err := parsed.Unpack(&results, unpackMethodName, output)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		results[i] = *result.(*interface{})
+	}
+	return results, nil
+}
+
+// FormatAbiValue presents a value unpacked by the abi as a string, with
+// addresses, hashes and byte arrays in hex.
+func FormatAbiValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case common.Address:
+		return typedValue.Hex()
+	case common.Hash:
+		return typedValue.Hex()
+	case []byte:
+		return hexutil.Encode(typedValue)
+	case *big.Int:
+		return typedValue.String()
+	}
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() == reflect.Array && reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+		bytes := make([]byte, reflectValue.Len())
+		reflect.Copy(reflect.ValueOf(bytes), reflectValue)
+		return hexutil.Encode(bytes)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// ParseAbiArguments converts arguments given as strings, e.g. on the command
+// line, into the Go values the abi packs for each input.
+func ParseAbiArguments(inputs []abi.Argument, arguments []string) ([]interface{}, error) {
+	if len(inputs) != len(arguments) {
+		return nil, ErrInvalidArgument
+	}
+	var values []interface{}
+	for i, input := range inputs {
+		value, err := parseAbiValue(input.Type, arguments[i])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func parseAbiValue(argumentType abi.Type, argument string) (interface{}, error) {
+	switch argumentType.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(argument) {
+			return nil, ErrInvalidArgument
+		}
+		return common.HexToAddress(argument), nil
+	case abi.BoolTy:
+		value, err := strconv.ParseBool(argument)
+		if err != nil {
+			return nil, ErrInvalidArgument
+		}
+		return value, nil
+	case abi.StringTy:
+		return argument, nil
+	case abi.IntTy, abi.UintTy:
+		return parseAbiInteger(argumentType, argument)
+	case abi.BytesTy:
+		value, err := hexutil.Decode(argument)
+		if err != nil {
+			return nil, ErrInvalidArgument
+		}
+		return value, nil
+	case abi.FixedBytesTy:
+		bytes, err := hexutil.Decode(argument)
+		if err != nil || len(bytes) > argumentType.Size {
+			return nil, ErrInvalidArgument
+		}
+		value := reflect.New(argumentType.Type).Elem()
+		reflect.Copy(value, reflect.ValueOf(bytes))
+		return value.Interface(), nil
+	}
+	return nil, ErrUnsupportedArgumentType
+}
+
+func parseAbiInteger(argumentType abi.Type, argument string) (interface{}, error) {
+	integer, ok := new(big.Int).SetString(argument, 0)
+	if !ok {
+		return nil, ErrInvalidArgument
+	}
+	if argumentType.T == abi.UintTy && (integer.Sign() < 0 || integer.BitLen() > argumentType.Size) {
+		return nil, ErrInvalidArgument
+	}
+	if argumentType.Kind == reflect.Ptr {
+		if argumentType.T == abi.IntTy && integer.BitLen() >= argumentType.Size {
+			return nil, ErrInvalidArgument
+		}
+		return integer, nil
+	}
+	value := reflect.New(argumentType.Type).Elem()
+	if argumentType.T == abi.UintTy {
+		value.SetUint(integer.Uint64())
+		return value.Interface(), nil
+	}
+	if !integer.IsInt64() || value.OverflowInt(integer.Int64()) {
+		return nil, ErrInvalidArgument
+	}
+	value.SetInt(integer.Int64())
+	return value.Interface(), nil
+}
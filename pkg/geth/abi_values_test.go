@@ -0,0 +1,74 @@
+package geth_test
+
+import (
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/testing"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parsing method arguments", func() {
+
+	parseArgument := func(solidityType string, argument string) (interface{}, error) {
+		argumentType, err := abi.NewType(solidityType)
+		Expect(err).ToNot(HaveOccurred())
+		values, err := geth.ParseAbiArguments([]abi.Argument{{Type: argumentType}}, []string{argument})
+		if err != nil {
+			return nil, err
+		}
+		return values[0], nil
+	}
+
+	It("parses each argument into the type of the input", func() {
+		Expect(parseArgument("address", "0xd26114cd6EE289AccF82350c8d8487fedB8A0C07")).
+			To(Equal(common.HexToAddress("0xd26114cd6EE289AccF82350c8d8487fedB8A0C07")))
+		Expect(parseArgument("bool", "true")).To(Equal(true))
+		Expect(parseArgument("string", "OMG")).To(Equal("OMG"))
+		Expect(parseArgument("uint256", "1000000000000000000000")).
+			To(Equal(new(big.Int).Mul(big.NewInt(1000000000000), big.NewInt(1000000000))))
+		Expect(parseArgument("uint8", "18")).To(Equal(uint8(18)))
+		Expect(parseArgument("int64", "-5")).To(Equal(int64(-5)))
+		Expect(parseArgument("bytes", "0x0102")).To(Equal([]byte{1, 2}))
+		Expect(parseArgument("bytes4", "0x01020304")).To(Equal([4]byte{1, 2, 3, 4}))
+	})
+
+	It("returns an error for an argument that does not fit the input", func() {
+		_, err := parseArgument("address", "0x123")
+		Expect(err).To(Equal(geth.ErrInvalidArgument))
+		_, err = parseArgument("uint8", "256")
+		Expect(err).To(Equal(geth.ErrInvalidArgument))
+		_, err = parseArgument("uint256", "-1")
+		Expect(err).To(Equal(geth.ErrInvalidArgument))
+		_, err = parseArgument("bool", "maybe")
+		Expect(err).To(Equal(geth.ErrInvalidArgument))
+	})
+
+	It("returns an error for array inputs", func() {
+		_, err := parseArgument("uint256[]", "1")
+
+		Expect(err).To(Equal(geth.ErrUnsupportedArgumentType))
+	})
+
+	It("returns an error when the number of arguments does not match the inputs", func() {
+		contractAbi, _ := geth.ParseAbi(testing.SampleContract().Abi)
+
+		_, err := geth.ParseAbiArguments(contractAbi.Methods["balanceOf"].Inputs, []string{})
+
+		Expect(err).To(Equal(geth.ErrInvalidArgument))
+	})
+
+	It("parses arguments that can be packed for the method", func() {
+		contractAbi, _ := geth.ParseAbi(testing.SampleContract().Abi)
+		values, err := geth.ParseAbiArguments(contractAbi.Methods["allowance"].Inputs,
+			[]string{"0xd26114cd6EE289AccF82350c8d8487fedB8A0C07", "0xfbb1b73c4f0bda4f67dca266ce6ef42f520fbb98"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = contractAbi.Pack("allowance", values...)
+
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,207 @@
+package geth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Largest number of calls sent to the node in one batch request.
+const maxBatchSize = 100
+
+type BatchClient interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+type rpcBlock struct {
+	Size         hexutil.Uint64   `json:"size"`
+	Transactions []rpcTransaction `json:"transactions"`
+}
+
+type rpcTransaction struct {
+	tx   *types.Transaction
+	from common.Address
+}
+
+func (transaction *rpcTransaction) UnmarshalJSON(input []byte) error {
+	if err := json.Unmarshal(input, &transaction.tx); err != nil {
+		return err
+	}
+	var extraInfo struct {
+		From common.Address `json:"from"`
+	}
+	if err := json.Unmarshal(input, &extraInfo); err != nil {
+		return err
+	}
+	transaction.from = extraInfo.From
+	return nil
+}
+
+// prefetchedClient answers GethBlockToCoreBlock with the senders and receipts
+// already retrieved in batches.
+type prefetchedClient struct {
+	senders  map[common.Hash]common.Address
+	receipts map[common.Hash]*types.Receipt
+	code     map[common.Address][]byte
+}
+
+func (client prefetchedClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	return client.senders[tx.Hash()], nil
+}
+
+func (client prefetchedClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return client.receipts[txHash], nil
+}
+
+func (client prefetchedClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return client.code[account], nil
+}
+
+func (blockchain *GethBlockchain) GetBlocksByNumber(ctx context.Context, blockNumbers []int64) ([]core.Block, error) {
+	return GetBlocksByNumber(ctx, blockchain.batchClient, blockNumbers)
+}
+
+// GetBlocksByNumber retrieves the blocks with their transactions, the
+// senders of the transactions and their receipts in batch requests, rather
+// than making a request per transaction.
+func GetBlocksByNumber(ctx context.Context, client BatchClient, blockNumbers []int64) ([]core.Block, error) {
+	rawBlocks := make([]json.RawMessage, len(blockNumbers))
+	var blockRequests []rpc.BatchElem
+	for i, blockNumber := range blockNumbers {
+		blockRequests = append(blockRequests, rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(uint64(blockNumber)), true},
+			Result: &rawBlocks[i],
+		})
+	}
+	err := batchCall(ctx, client, blockRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]*types.Header, len(blockNumbers))
+	bodies := make([]rpcBlock, len(blockNumbers))
+	prefetched := prefetchedClient{
+		senders:  make(map[common.Hash]common.Address),
+		receipts: make(map[common.Hash]*types.Receipt),
+		code:     make(map[common.Address][]byte),
+	}
+	var transactionHashes []common.Hash
+	for i, rawBlock := range rawBlocks {
+		if len(rawBlock) == 0 || string(rawBlock) == "null" {
+			return nil, ethereum.NotFound
+		}
+		if err := json.Unmarshal(rawBlock, &headers[i]); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(rawBlock, &bodies[i]); err != nil {
+			return nil, err
+		}
+		for _, transaction := range bodies[i].Transactions {
+			prefetched.senders[transaction.tx.Hash()] = transaction.from
+			transactionHashes = append(transactionHashes, transaction.tx.Hash())
+		}
+	}
+
+	receipts := make([]*types.Receipt, len(transactionHashes))
+	var receiptRequests []rpc.BatchElem
+	for i, transactionHash := range transactionHashes {
+		receiptRequests = append(receiptRequests, rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{transactionHash},
+			Result: &receipts[i],
+		})
+	}
+	err = batchCall(ctx, client, receiptRequests)
+	if err != nil {
+		return nil, err
+	}
+	for i, transactionHash := range transactionHashes {
+		prefetched.receipts[transactionHash] = receipts[i]
+	}
+
+	// the code of the contracts created, which is left out when the node
+	// answers with an error because it has pruned the state of the block
+	var createdContracts []common.Address
+	var codeRequests []rpc.BatchElem
+	for i, header := range headers {
+		for _, transaction := range bodies[i].Transactions {
+			receipt := prefetched.receipts[transaction.tx.Hash()]
+			if transaction.tx.To() != nil || receipt == nil || receipt.ContractAddress == (common.Address{}) {
+				continue
+			}
+			createdContracts = append(createdContracts, receipt.ContractAddress)
+			codeRequests = append(codeRequests, rpc.BatchElem{
+				Method: "eth_getCode",
+				Args:   []interface{}{receipt.ContractAddress, hexutil.EncodeBig(header.Number)},
+				Result: new(hexutil.Bytes),
+			})
+		}
+	}
+	err = batchCallAllowingFailures(ctx, client, codeRequests)
+	if err != nil {
+		return nil, err
+	}
+	for i, request := range codeRequests {
+		switch {
+		case request.Error == nil:
+			prefetched.code[createdContracts[i]] = *request.Result.(*hexutil.Bytes)
+		case !isNodeError(request.Error):
+			return nil, fmt.Errorf("%s: %v", request.Method, request.Error)
+		}
+	}
+
+	var blocks []core.Block
+	for i, header := range headers {
+		var transactions []*types.Transaction
+		for _, transaction := range bodies[i].Transactions {
+			transactions = append(transactions, transaction.tx)
+		}
+		gethBlock := types.NewBlockWithHeader(header).WithBody(transactions, nil)
+		block, err := GethBlockToCoreBlock(ctx, gethBlock, prefetched)
+		if err != nil {
+			return nil, err
+		}
+		// the size reported by the node includes the uncles, which are not retrieved
+		block.Size = int64(bodies[i].Size)
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func batchCall(ctx context.Context, client BatchClient, requests []rpc.BatchElem) error {
+	err := batchCallAllowingFailures(ctx, client, requests)
+	if err != nil {
+		return err
+	}
+	for _, request := range requests {
+		if request.Error != nil {
+			return fmt.Errorf("%s: %v", request.Method, request.Error)
+		}
+	}
+	return nil
+}
+
+// batchCallAllowingFailures only returns an error when a batch request
+// fails, leaving the errors of the individual calls on the requests.
+func batchCallAllowingFailures(ctx context.Context, client BatchClient, requests []rpc.BatchElem) error {
+	for start := 0; start < len(requests); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		err := client.BatchCallContext(ctx, requests[start:end])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
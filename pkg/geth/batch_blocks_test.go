@@ -0,0 +1,211 @@
+package geth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type FakeBatchClient struct {
+	results      map[string]interface{}
+	errors       map[string]error
+	batchSizes   []int
+	requestError error
+}
+
+func NewFakeBatchClient() *FakeBatchClient {
+	return &FakeBatchClient{results: make(map[string]interface{}), errors: make(map[string]error)}
+}
+
+// SetError makes the node answer the call with err.
+func (client *FakeBatchClient) SetError(method string, argument string, err error) {
+	client.errors[method+argument] = err
+}
+
+func (client *FakeBatchClient) SetResult(method string, argument string, result interface{}) {
+	client.results[method+argument] = result
+}
+
+func (client *FakeBatchClient) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	client.batchSizes = append(client.batchSizes, len(batch))
+	if client.requestError != nil {
+		return client.requestError
+	}
+	for i, request := range batch {
+		argument, _ := json.Marshal(request.Args[0])
+		key := request.Method + strings.Trim(string(argument), `"`)
+		if err, ok := client.errors[key]; ok {
+			batch[i].Error = err
+			continue
+		}
+		result, ok := client.results[key]
+		if !ok {
+			result = nil
+		}
+		encoded, _ := json.Marshal(result)
+		batch[i].Error = json.Unmarshal(encoded, request.Result)
+	}
+	return nil
+}
+
+func rpcBlockResult(header *types.Header, transactions []*types.Transaction, from common.Address, size uint64) map[string]interface{} {
+	var result map[string]interface{}
+	encodedHeader, _ := json.Marshal(header)
+	json.Unmarshal(encodedHeader, &result)
+	var rpcTransactions []map[string]interface{}
+	for _, transaction := range transactions {
+		var rpcTransaction map[string]interface{}
+		encodedTransaction, _ := json.Marshal(transaction)
+		json.Unmarshal(encodedTransaction, &rpcTransaction)
+		rpcTransaction["from"] = from.Hex()
+		rpcTransactions = append(rpcTransactions, rpcTransaction)
+	}
+	result["transactions"] = rpcTransactions
+	result["size"] = fmt.Sprintf("0x%x", size)
+	return result
+}
+
+var _ = Describe("Fetching blocks in batches", func() {
+	var client *FakeBatchClient
+	var sender common.Address
+	var transaction *types.Transaction
+
+	header := func(number int64) *types.Header {
+		return &types.Header{
+			Difficulty: big.NewInt(1),
+			GasLimit:   big.NewInt(100000),
+			GasUsed:    big.NewInt(21000),
+			Number:     big.NewInt(number),
+			Time:       big.NewInt(140000000),
+		}
+	}
+
+	BeforeEach(func() {
+		client = NewFakeBatchClient()
+		key, _ := crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		unsigned := types.NewTransaction(1, common.HexToAddress("0x456"), big.NewInt(10), big.NewInt(21000), big.NewInt(5), []byte{1, 2})
+		transaction, _ = types.SignTx(unsigned, types.HomesteadSigner{}, key)
+		client.SetResult("eth_getBlockByNumber", "0x1", rpcBlockResult(header(1), []*types.Transaction{transaction}, sender, 600))
+		client.SetResult("eth_getBlockByNumber", "0x2", rpcBlockResult(header(2), nil, sender, 500))
+		client.SetResult("eth_getTransactionReceipt", transaction.Hash().Hex(), &types.Receipt{
+			TxHash:            transaction.Hash(),
+			CumulativeGasUsed: big.NewInt(21000),
+			GasUsed:           big.NewInt(21000),
+			Logs:              []*types.Log{},
+			Status:            1,
+		})
+	})
+
+	It("converts the blocks with their transactions, senders and receipts", func() {
+		blocks, err := geth.GetBlocksByNumber(context.Background(), client, []int64{1, 2})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(blocks)).To(Equal(2))
+		Expect(blocks[0].Number).To(Equal(int64(1)))
+		Expect(blocks[0].Hash).To(Equal(header(1).Hash().Hex()))
+		Expect(blocks[0].Size).To(Equal(int64(600)))
+		Expect(len(blocks[0].Transactions)).To(Equal(1))
+		coreTransaction := blocks[0].Transactions[0]
+		Expect(coreTransaction.Hash).To(Equal(transaction.Hash().Hex()))
+		Expect(coreTransaction.From).To(Equal(strings.ToLower(sender.Hex())))
+		Expect(coreTransaction.To).To(Equal(strings.ToLower(common.HexToAddress("0x456").Hex())))
+		Expect(coreTransaction.Data).To(Equal([]byte{1, 2}))
+		Expect(coreTransaction.Receipt.TxHash).To(Equal(transaction.Hash().Hex()))
+		Expect(coreTransaction.Receipt.GasUsed).To(Equal(int64(21000)))
+		Expect(blocks[1].Number).To(Equal(int64(2)))
+		Expect(blocks[1].Transactions).To(BeEmpty())
+	})
+
+	It("makes one batch request for the blocks and one for the receipts", func() {
+		geth.GetBlocksByNumber(context.Background(), client, []int64{1, 2})
+
+		Expect(client.batchSizes).To(Equal([]int{2, 1}))
+	})
+
+	It("fetches the code of the contracts created", func() {
+		key, _ := crypto.GenerateKey()
+		creation, _ := types.SignTx(types.NewContractCreation(2, big.NewInt(0), big.NewInt(100000), big.NewInt(5), []byte{1}), types.HomesteadSigner{}, key)
+		contractAddress := common.HexToAddress("0xabc")
+		client.SetResult("eth_getBlockByNumber", "0x3", rpcBlockResult(header(3), []*types.Transaction{creation}, sender, 600))
+		client.SetResult("eth_getTransactionReceipt", creation.Hash().Hex(), &types.Receipt{
+			TxHash:            creation.Hash(),
+			ContractAddress:   contractAddress,
+			CumulativeGasUsed: big.NewInt(100000),
+			GasUsed:           big.NewInt(100000),
+			Logs:              []*types.Log{},
+			Status:            1,
+		})
+		client.SetResult("eth_getCode", strings.ToLower(contractAddress.Hex()), hexutil.Bytes{0x60, 0x60})
+
+		blocks, err := geth.GetBlocksByNumber(context.Background(), client, []int64{3})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.batchSizes).To(Equal([]int{1, 1, 1}))
+		coreTransaction := blocks[0].Transactions[0]
+		Expect(coreTransaction.Receipt.ContractAddress).To(Equal(contractAddress.Hex()))
+		Expect(coreTransaction.Receipt.ContractCodeHash).To(Equal(crypto.Keccak256Hash([]byte{0x60, 0x60}).Hex()))
+	})
+
+	It("leaves out the code hash when the node has pruned the state of the block", func() {
+		key, _ := crypto.GenerateKey()
+		creation, _ := types.SignTx(types.NewContractCreation(2, big.NewInt(0), big.NewInt(100000), big.NewInt(5), []byte{1}), types.HomesteadSigner{}, key)
+		contractAddress := common.HexToAddress("0xabc")
+		client.SetResult("eth_getBlockByNumber", "0x3", rpcBlockResult(header(3), []*types.Transaction{creation}, sender, 600))
+		client.SetResult("eth_getTransactionReceipt", creation.Hash().Hex(), &types.Receipt{
+			TxHash:            creation.Hash(),
+			ContractAddress:   contractAddress,
+			CumulativeGasUsed: big.NewInt(100000),
+			GasUsed:           big.NewInt(100000),
+			Logs:              []*types.Log{},
+			Status:            1,
+		})
+		client.SetError("eth_getCode", strings.ToLower(contractAddress.Hex()), nodeError{})
+
+		blocks, err := geth.GetBlocksByNumber(context.Background(), client, []int64{3})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blocks[0].Transactions[0].Receipt.ContractCodeHash).To(BeEmpty())
+	})
+
+	It("splits large batches", func() {
+		var blockNumbers []int64
+		for number := int64(1); number <= 150; number++ {
+			client.SetResult("eth_getBlockByNumber", fmt.Sprintf("0x%x", number), rpcBlockResult(header(number), nil, sender, 500))
+			blockNumbers = append(blockNumbers, number)
+		}
+
+		blocks, err := geth.GetBlocksByNumber(context.Background(), client, blockNumbers)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(blocks)).To(Equal(150))
+		Expect(client.batchSizes).To(Equal([]int{100, 50}))
+	})
+
+	It("returns an error when a block does not exist", func() {
+		blocks, err := geth.GetBlocksByNumber(context.Background(), client, []int64{1, 3})
+
+		Expect(err).To(HaveOccurred())
+		Expect(blocks).To(BeNil())
+	})
+
+	It("returns an error when the batch request fails", func() {
+		client.requestError = errors.New("connection refused")
+
+		_, err := geth.GetBlocksByNumber(context.Background(), client, []int64{1})
+
+		Expect(err).To(MatchError("connection refused"))
+	})
+})
@@ -0,0 +1,94 @@
+package geth
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+type BlockSource interface {
+	LastBlock(ctx context.Context) (*big.Int, error)
+	GetBlockByNumber(ctx context.Context, blockNumber int64) (core.Block, error)
+}
+
+// BlockPoller finds new blocks by polling the latest header, for nodes
+// reached over HTTP where eth_subscribe is not available. Every block after
+// the head seen when subscribing is sent in order, even when several are
+// mined between polls.
+type BlockPoller struct {
+	source          BlockSource
+	interval        time.Duration
+	outputBlocks    chan core.Block
+	lastBlockNumber int64
+	stop            chan struct{}
+	stopOnce        *sync.Once
+}
+
+func NewBlockPoller(source BlockSource, interval time.Duration) *BlockPoller {
+	return &BlockPoller{source: source, interval: interval}
+}
+
+// Subscribe starts from the current head, so only blocks mined afterwards
+// are sent.
+func (poller *BlockPoller) Subscribe(ctx context.Context, blocks chan core.Block) error {
+	head, err := poller.source.LastBlock(ctx)
+	if err != nil {
+		return err
+	}
+	poller.outputBlocks = blocks
+	poller.lastBlockNumber = head.Int64()
+	poller.stop = make(chan struct{})
+	poller.stopOnce = &sync.Once{}
+	return nil
+}
+
+// Listen polls until the context is done or the poller is stopped, and
+// returns the error of the first request that fails.
+func (poller *BlockPoller) Listen(ctx context.Context) error {
+	ticker := time.NewTicker(poller.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := poller.sendNewBlocks(ctx)
+			if err != nil {
+				return err
+			}
+		case <-poller.stop:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (poller *BlockPoller) Stop() {
+	if poller.stopOnce != nil {
+		poller.stopOnce.Do(func() { close(poller.stop) })
+	}
+}
+
+func (poller *BlockPoller) sendNewBlocks(ctx context.Context) error {
+	head, err := poller.source.LastBlock(ctx)
+	if err != nil {
+		return err
+	}
+	for blockNumber := poller.lastBlockNumber + 1; blockNumber <= head.Int64(); blockNumber++ {
+		block, err := poller.source.GetBlockByNumber(ctx, blockNumber)
+		if err != nil {
+			return err
+		}
+		select {
+		case poller.outputBlocks <- block:
+		case <-poller.stop:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		poller.lastBlockNumber = blockNumber
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package geth_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type FakeBlockSource struct {
+	mutex     sync.Mutex
+	head      int64
+	headError error
+}
+
+func (source *FakeBlockSource) Mine(head int64) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.head = head
+}
+
+func (source *FakeBlockSource) FailHeadRequests(err error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.headError = err
+}
+
+func (source *FakeBlockSource) LastBlock(ctx context.Context) (*big.Int, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return big.NewInt(source.head), source.headError
+}
+
+func (source *FakeBlockSource) GetBlockByNumber(ctx context.Context, blockNumber int64) (core.Block, error) {
+	return core.Block{Number: blockNumber}, nil
+}
+
+var _ = Describe("Polling for new blocks", func() {
+	var source *FakeBlockSource
+	var poller *geth.BlockPoller
+	var blocks chan core.Block
+
+	BeforeEach(func() {
+		source = &FakeBlockSource{head: 10}
+		poller = geth.NewBlockPoller(source, time.Millisecond)
+		blocks = make(chan core.Block, 10)
+		poller.Subscribe(context.Background(), blocks)
+	})
+
+	It("sends every block mined since the last poll in order", func(done Done) {
+		go poller.Listen(context.Background())
+		defer poller.Stop()
+
+		source.Mine(13)
+
+		Expect((<-blocks).Number).To(Equal(int64(11)))
+		Expect((<-blocks).Number).To(Equal(int64(12)))
+		Expect((<-blocks).Number).To(Equal(int64(13)))
+		close(done)
+	}, 1)
+
+	It("does not send the blocks mined before subscribing", func() {
+		go poller.Listen(context.Background())
+
+		time.Sleep(10 * time.Millisecond)
+		poller.Stop()
+
+		Expect(blocks).To(BeEmpty())
+	})
+
+	It("returns the error of a failed poll", func(done Done) {
+		source.FailHeadRequests(errors.New("503 Service Unavailable"))
+
+		err := poller.Listen(context.Background())
+
+		Expect(err).To(MatchError("503 Service Unavailable"))
+		close(done)
+	}, 1)
+
+	It("stops when told to", func(done Done) {
+		stopped := make(chan error)
+		go func() {
+			stopped <- poller.Listen(context.Background())
+		}()
+
+		poller.Stop()
+
+		Expect(<-stopped).To(BeNil())
+		close(done)
+	}, 1)
+
+	It("fails to subscribe when the head cannot be retrieved", func() {
+		source.FailHeadRequests(errors.New("connection refused"))
+
+		err := poller.Subscribe(context.Background(), blocks)
+
+		Expect(err).To(MatchError("connection refused"))
+	})
+})
@@ -0,0 +1,66 @@
+package geth
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const methodSelectorLength = 4
+
+var (
+	ErrNoMatchingMethod = errors.New("no matching method in abi")
+	ErrInvalidCallData  = errors.New("transaction input does not match method")
+)
+
+func DecodeTransactions(contractAbi abi.ABI, transactions []core.Transaction) []core.DecodedCall {
+	var decodedCalls []core.DecodedCall
+	for _, transaction := range transactions {
+		decodedCall, err := DecodeTransaction(contractAbi, transaction)
+		if err != nil {
+			continue
+		}
+		decodedCalls = append(decodedCalls, decodedCall)
+	}
+	return decodedCalls
+}
+
+func DecodeTransaction(contractAbi abi.ABI, transaction core.Transaction) (core.DecodedCall, error) {
+	if len(transaction.Data) < methodSelectorLength {
+		return core.DecodedCall{}, ErrNoMatchingMethod
+	}
+	method, ok := findMethod(contractAbi, transaction.Data[:methodSelectorLength])
+	if !ok {
+		return core.DecodedCall{}, ErrNoMatchingMethod
+	}
+	values, err := unpackArguments(method.Inputs, transaction.Data[methodSelectorLength:])
+	if err != nil {
+		return core.DecodedCall{}, ErrInvalidCallData
+	}
+	var arguments []core.CallArgument
+	for i, input := range method.Inputs {
+		arguments = append(arguments, core.CallArgument{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: FormatAbiValue(values[i]),
+		})
+	}
+	return core.DecodedCall{
+		TxHash:    transaction.Hash,
+		To:        transaction.To,
+		From:      transaction.From,
+		Method:    method.Name,
+		Arguments: arguments,
+	}, nil
+}
+
+func findMethod(contractAbi abi.ABI, selector []byte) (abi.Method, bool) {
+	for _, method := range contractAbi.Methods {
+		if bytes.Equal(method.Id(), selector) {
+			return method, true
+		}
+	}
+	return abi.Method{}, false
+}
@@ -0,0 +1,91 @@
+package geth_test
+
+import (
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/testing"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decoding transaction input", func() {
+
+	var contractAbi abi.ABI
+	var transaction core.Transaction
+
+	BeforeEach(func() {
+		contractAbi, _ = geth.ParseAbi(testing.SampleContract().Abi)
+		input, err := contractAbi.Pack("transfer", common.HexToAddress("0xfbb1b73c4f0bda4f67dca266ce6ef42f520fbb98"), big.NewInt(900))
+		Expect(err).ToNot(HaveOccurred())
+		transaction = core.Transaction{
+			Hash: "0xf896bfd1eb539d881a1a31102b78de9f25cd591bf1fe1924b86148c0b205fd5d",
+			Data: input,
+			To:   "0xd26114cd6ee289accf82350c8d8487fedb8a0c07",
+			From: "0xd26114cd6ee289accf82350c8d8487fedb8a0c08",
+		}
+	})
+
+	It("decodes the method and arguments of a transaction", func() {
+		decodedCall, err := geth.DecodeTransaction(contractAbi, transaction)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodedCall).To(Equal(core.DecodedCall{
+			TxHash: transaction.Hash,
+			To:     transaction.To,
+			From:   transaction.From,
+			Method: "transfer",
+			Arguments: []core.CallArgument{
+				{Name: "_to", Type: "address", Value: "0xFBb1b73C4f0BDa4f67dcA266ce6Ef42f520fBB98"},
+				{Name: "_value", Type: "uint256", Value: "900"},
+			},
+		}))
+	})
+
+	It("decodes a method without arguments", func() {
+		input, _ := contractAbi.Pack("totalSupply")
+		transaction.Data = input
+
+		decodedCall, err := geth.DecodeTransaction(contractAbi, transaction)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodedCall.Method).To(Equal("totalSupply"))
+		Expect(decodedCall.Arguments).To(BeEmpty())
+	})
+
+	It("returns an error when no method in the abi matches the selector", func() {
+		transaction.Data = []byte{1, 2, 3, 4}
+
+		_, err := geth.DecodeTransaction(contractAbi, transaction)
+
+		Expect(err).To(Equal(geth.ErrNoMatchingMethod))
+	})
+
+	It("returns an error when the transaction has no input", func() {
+		transaction.Data = nil
+
+		_, err := geth.DecodeTransaction(contractAbi, transaction)
+
+		Expect(err).To(Equal(geth.ErrNoMatchingMethod))
+	})
+
+	It("returns an error when the input does not match the method", func() {
+		transaction.Data = transaction.Data[:10]
+
+		_, err := geth.DecodeTransaction(contractAbi, transaction)
+
+		Expect(err).To(Equal(geth.ErrInvalidCallData))
+	})
+
+	It("skips transactions that cannot be decoded", func() {
+		plainTransfer := core.Transaction{Hash: "0x1"}
+
+		decodedCalls := geth.DecodeTransactions(contractAbi, []core.Transaction{plainTransfer, transaction})
+
+		Expect(len(decodedCalls)).To(Equal(1))
+		Expect(decodedCalls[0].TxHash).To(Equal(transaction.Hash))
+	})
+})
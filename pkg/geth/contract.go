@@ -2,6 +2,8 @@ package geth
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"sort"
 
@@ -10,49 +12,101 @@ import (
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
 	ErrInvalidStateAttribute = errors.New("invalid state attribute")
+	ErrNonConstantMethod     = errors.New("method is not constant")
 )
 
-func (blockchain *GethBlockchain) GetAttribute(contract core.Contract, attributeName string, blockNumber *big.Int) (interface{}, error) {
+func (blockchain *GethBlockchain) GetAttribute(ctx context.Context, contract core.Contract, attributeName string, blockNumber *big.Int) (interface{}, error) {
+	return blockchain.GetAttributeWithArguments(ctx, contract, attributeName, nil, blockNumber)
+}
+
+// GetAttributeWithArguments calls a constant method of the contract, such as
+// balanceOf(address), parsing each argument into the type of the method input.
+func (blockchain *GethBlockchain) GetAttributeWithArguments(ctx context.Context, contract core.Contract, attributeName string, arguments []string, blockNumber *big.Int) (interface{}, error) {
 	parsed, err := ParseAbi(contract.Abi)
 	var result interface{}
 	if err != nil {
 		return result, err
 	}
-	input, err := parsed.Pack(attributeName)
+	method, ok := parsed.Methods[attributeName]
+	if !ok {
+		return nil, ErrInvalidStateAttribute
+	}
+	if !method.Const {
+		return nil, ErrNonConstantMethod
+	}
+	values, err := ParseAbiArguments(method.Inputs, arguments)
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack(attributeName, values...)
 	if err != nil {
 		return nil, ErrInvalidStateAttribute
 	}
-	output, err := callContract(contract.Hash, input, blockchain, blockNumber)
+	output, err := callContract(ctx, contract.Hash, input, blockchain, blockNumber)
 	if err != nil {
 		return nil, err
 	}
-	err = parsed.Unpack(&result, attributeName, output)
+	return UnpackAttribute(method, output)
+}
+
+// UnpackAttribute decodes the output of a call to a constant method. Methods
+// with several outputs are returned as a core.AttributeTuple.
+func UnpackAttribute(method abi.Method, output []byte) (interface{}, error) {
+	if len(method.Outputs) == 0 {
+		return nil, nil
+	}
+	values, err := unpackArguments(method.Outputs, output)
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	if len(method.Outputs) == 1 {
+		return values[0], nil
+	}
+	var tuple core.AttributeTuple
+	for i, methodOutput := range method.Outputs {
+		tuple = append(tuple, core.AttributeComponent{
+			Name:  methodOutput.Name,
+			Type:  methodOutput.Type.String(),
+			Value: values[i],
+		})
+	}
+	return tuple, nil
 }
 
-func callContract(contractHash string, input []byte, blockchain *GethBlockchain, blockNumber *big.Int) ([]byte, error) {
+func callContract(ctx context.Context, contractHash string, input []byte, blockchain *GethBlockchain, blockNumber *big.Int) ([]byte, error) {
 	to := common.HexToAddress(contractHash)
 	msg := ethereum.CallMsg{To: &to, Data: input}
-	return blockchain.client.CallContract(context.Background(), msg, blockNumber)
+	return blockchain.client.CallContract(ctx, msg, blockNumber)
 }
 
-func (blockchain *GethBlockchain) GetAttributes(contract core.Contract) (core.ContractAttributes, error) {
+func (blockchain *GethBlockchain) GetAttributes(ctx context.Context, contract core.Contract) (core.ContractAttributes, error) {
 	parsed, _ := ParseAbi(contract.Abi)
 	var contractAttributes core.ContractAttributes
 	for _, abiElement := range parsed.Methods {
 		if (len(abiElement.Outputs) > 0) && (len(abiElement.Inputs) == 0) && abiElement.Const {
-			attributeType := abiElement.Outputs[0].Type.String()
-			contractAttributes = append(contractAttributes, core.ContractAttribute{abiElement.Name, attributeType})
+			contractAttributes = append(contractAttributes, contractAttribute(abiElement))
 		}
 	}
 	sort.Sort(contractAttributes)
 	return contractAttributes, nil
 }
+
+func contractAttribute(method abi.Method) core.ContractAttribute {
+	var outputs []core.AttributeOutput
+	var outputTypes []string
+	for _, output := range method.Outputs {
+		outputs = append(outputs, core.AttributeOutput{Name: output.Name, Type: output.Type.String()})
+		outputTypes = append(outputTypes, output.Type.String())
+	}
+	attributeType := outputTypes[0]
+	if len(outputTypes) > 1 {
+		attributeType = fmt.Sprintf("(%s)", strings.Join(outputTypes, ","))
+	}
+	return core.ContractAttribute{Name: method.Name, Type: attributeType, Outputs: outputs}
+}
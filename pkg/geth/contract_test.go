@@ -0,0 +1,90 @@
+package geth_test
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const reservesAbi = `[{"constant":true,"inputs":[],"name":"getReserves","payable":false,"type":"function",
+	"outputs":[{"name":"reserve0","type":"uint112"},{"name":"reserve1","type":"uint112"},{"name":"","type":"uint32"}]},
+	{"constant":true,"inputs":[],"name":"token0","payable":false,"type":"function",
+	"outputs":[{"name":"","type":"address"}]}]`
+
+const transferAbi = `[{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],
+	"name":"transfer","payable":false,"type":"function","outputs":[{"name":"","type":"bool"}]}]`
+
+var _ = Describe("Contract attributes", func() {
+
+	It("describes every output of an attribute", func() {
+		blockchain := &geth.GethBlockchain{}
+
+		attributes, err := blockchain.GetAttributes(context.Background(), core.Contract{Abi: reservesAbi})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attributes).To(Equal(core.ContractAttributes{
+			{
+				Name: "getReserves",
+				Type: "(uint112,uint112,uint32)",
+				Outputs: []core.AttributeOutput{
+					{Name: "reserve0", Type: "uint112"},
+					{Name: "reserve1", Type: "uint112"},
+					{Name: "", Type: "uint32"},
+				},
+			},
+			{
+				Name:    "token0",
+				Type:    "address",
+				Outputs: []core.AttributeOutput{{Name: "", Type: "address"}},
+			},
+		}))
+	})
+
+	It("unpacks an attribute with several outputs into a tuple", func() {
+		contractAbi, _ := geth.ParseAbi(reservesAbi)
+		method := contractAbi.Methods["getReserves"]
+		output := packOutputs(method, big.NewInt(100), big.NewInt(200), uint32(1514764800))
+
+		result, err := geth.UnpackAttribute(method, output)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(core.AttributeTuple{
+			{Name: "reserve0", Type: "uint112", Value: big.NewInt(100)},
+			{Name: "reserve1", Type: "uint112", Value: big.NewInt(200)},
+			{Name: "", Type: "uint32", Value: uint32(1514764800)},
+		}))
+	})
+
+	It("unpacks an attribute with one output into its value", func() {
+		contractAbi, _ := geth.ParseAbi(reservesAbi)
+		method := contractAbi.Methods["token0"]
+		address := [20]byte{1}
+		output := packOutputs(method, address)
+
+		result, err := geth.UnpackAttribute(method, output)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeEquivalentTo(address))
+	})
+
+	It("refuses to call a method that is not constant", func() {
+		blockchain := &geth.GethBlockchain{}
+		contract := core.Contract{Hash: "0x123", Abi: transferAbi}
+
+		_, err := blockchain.GetAttributeWithArguments(context.Background(), contract, "transfer", []string{"0x456", "1"}, nil)
+
+		Expect(err).To(Equal(geth.ErrNonConstantMethod))
+	})
+})
+
+func packOutputs(method abi.Method, values ...interface{}) []byte {
+	packer := abi.ABI{Methods: map[string]abi.Method{"outputs": {Name: "outputs", Inputs: method.Outputs}}}
+	packed, err := packer.Pack("outputs", values...)
+	Expect(err).ToNot(HaveOccurred())
+	return packed[4:]
+}
@@ -0,0 +1,67 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrContractNotDeployed = func(contractHash string) error {
+	return errors.New(fmt.Sprintf("Contract %v has no code at the last block", contractHash))
+}
+
+type CodeReader interface {
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// DeploymentReceipts finds the block of the receipt that created a contract
+// among the receipts already saved.
+type DeploymentReceipts interface {
+	FindDeploymentBlockNumber(contractHash string) (int64, error)
+}
+
+// FindDeploymentBlock returns the number of the block a contract was created
+// in. The saved receipts are used when they include the creation, otherwise
+// it binary searches for the first block with code at the contract address,
+// which needs a node keeping historical state. receipts may be nil.
+func FindDeploymentBlock(ctx context.Context, reader CodeReader, receipts DeploymentReceipts, contractHash string, lastBlockNumber int64) (int64, error) {
+	if receipts != nil {
+		blockNumber, err := receipts.FindDeploymentBlockNumber(contractHash)
+		if err == nil {
+			return blockNumber, nil
+		}
+	}
+	address := common.HexToAddress(contractHash)
+	deployed, err := hasCode(ctx, reader, address, lastBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	if !deployed {
+		return 0, ErrContractNotDeployed(contractHash)
+	}
+	low, high := int64(0), lastBlockNumber
+	for low < high {
+		middle := low + (high-low)/2
+		deployed, err := hasCode(ctx, reader, address, middle)
+		if err != nil {
+			return 0, err
+		}
+		if deployed {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+	return low, nil
+}
+
+func hasCode(ctx context.Context, reader CodeReader, address common.Address, blockNumber int64) (bool, error) {
+	code, err := reader.CodeAt(ctx, address, big.NewInt(blockNumber))
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
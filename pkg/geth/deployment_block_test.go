@@ -0,0 +1,98 @@
+package geth_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type FakeCodeReader struct {
+	deploymentBlock int64
+	requests        int
+	err             error
+}
+
+func (reader *FakeCodeReader) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	reader.requests++
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	if reader.deploymentBlock < 0 || blockNumber.Int64() < reader.deploymentBlock {
+		return []byte{}, nil
+	}
+	return []byte{0x60, 0x60}, nil
+}
+
+type FakeDeploymentReceipts struct {
+	blockNumbers map[string]int64
+}
+
+func (receipts FakeDeploymentReceipts) FindDeploymentBlockNumber(contractHash string) (int64, error) {
+	blockNumber, ok := receipts.blockNumbers[contractHash]
+	if !ok {
+		return 0, errors.New("no deployment receipt")
+	}
+	return blockNumber, nil
+}
+
+var _ = Describe("Finding the deployment block of a contract", func() {
+	It("finds the first block with code at the contract address", func() {
+		reader := &FakeCodeReader{deploymentBlock: 4719568}
+
+		blockNumber, err := geth.FindDeploymentBlock(context.Background(), reader, nil, "0x123", 5000000)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blockNumber).To(Equal(int64(4719568)))
+		Expect(reader.requests).To(BeNumerically("<=", 25))
+	})
+
+	It("finds a contract created at the last block or in the genesis block", func() {
+		blockNumber, _ := geth.FindDeploymentBlock(context.Background(), &FakeCodeReader{deploymentBlock: 100}, nil, "0x123", 100)
+		Expect(blockNumber).To(Equal(int64(100)))
+
+		blockNumber, _ = geth.FindDeploymentBlock(context.Background(), &FakeCodeReader{deploymentBlock: 0}, nil, "0x123", 100)
+		Expect(blockNumber).To(Equal(int64(0)))
+	})
+
+	It("uses the saved receipt that created the contract", func() {
+		reader := &FakeCodeReader{deploymentBlock: 10}
+		receipts := FakeDeploymentReceipts{blockNumbers: map[string]int64{"0x123": 10}}
+
+		blockNumber, err := geth.FindDeploymentBlock(context.Background(), reader, receipts, "0x123", 100)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blockNumber).To(Equal(int64(10)))
+		Expect(reader.requests).To(Equal(0))
+	})
+
+	It("searches the chain when no saved receipt created the contract", func() {
+		reader := &FakeCodeReader{deploymentBlock: 10}
+		receipts := FakeDeploymentReceipts{blockNumbers: map[string]int64{}}
+
+		blockNumber, err := geth.FindDeploymentBlock(context.Background(), reader, receipts, "0x123", 100)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blockNumber).To(Equal(int64(10)))
+	})
+
+	It("returns an error when there is no code at the address", func() {
+		reader := &FakeCodeReader{deploymentBlock: -1}
+
+		_, err := geth.FindDeploymentBlock(context.Background(), reader, nil, "0x123", 100)
+
+		Expect(err).To(MatchError(geth.ErrContractNotDeployed("0x123")))
+	})
+
+	It("returns the error of a failed request", func() {
+		reader := &FakeCodeReader{err: errors.New("missing trie node")}
+
+		_, err := geth.FindDeploymentBlock(context.Background(), reader, nil, "0x123", 100)
+
+		Expect(err).To(MatchError("missing trie node"))
+	})
+})
@@ -0,0 +1,125 @@
+package geth
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrNoMatchingEvent = errors.New("no matching event in abi")
+	ErrInvalidLogData  = errors.New("log data does not match event")
+)
+
+func DecodeLogs(contractAbi abi.ABI, logs []core.Log) []core.DecodedEvent {
+	var decodedEvents []core.DecodedEvent
+	for _, log := range logs {
+		decodedEvent, err := DecodeLog(contractAbi, log)
+		if err != nil {
+			continue
+		}
+		decodedEvents = append(decodedEvents, decodedEvent)
+	}
+	return decodedEvents
+}
+
+func DecodeLog(contractAbi abi.ABI, log core.Log) (core.DecodedEvent, error) {
+	event, ok := findEvent(contractAbi, log.Topics[0])
+	if !ok {
+		return core.DecodedEvent{}, ErrNoMatchingEvent
+	}
+	arguments, err := decodeEventArguments(event, log)
+	if err != nil {
+		return core.DecodedEvent{}, err
+	}
+	return core.DecodedEvent{
+		Address:     log.Address,
+		BlockNumber: log.BlockNumber,
+		LogIndex:    log.Index,
+		TxHash:      log.TxHash,
+		Name:        event.Name,
+		Arguments:   arguments,
+	}, nil
+}
+
+func findEvent(contractAbi abi.ABI, topic0 string) (abi.Event, bool) {
+	for _, event := range contractAbi.Events {
+		if !event.Anonymous && strings.EqualFold(event.Id().Hex(), topic0) {
+			return event, true
+		}
+	}
+	return abi.Event{}, false
+}
+
+func decodeEventArguments(event abi.Event, log core.Log) ([]core.EventArgument, error) {
+	var indexedInputs, nonIndexedInputs []abi.Argument
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexedInputs = append(indexedInputs, input)
+		} else {
+			nonIndexedInputs = append(nonIndexedInputs, input)
+		}
+	}
+	indexedValues, err := decodeIndexedValues(indexedInputs, log.Topics)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hexutil.Decode(log.Data)
+	if err != nil && len(nonIndexedInputs) > 0 {
+		return nil, ErrInvalidLogData
+	}
+	nonIndexedValues, err := unpackArguments(nonIndexedInputs, data)
+	if err != nil {
+		return nil, ErrInvalidLogData
+	}
+	var arguments []core.EventArgument
+	for _, input := range event.Inputs {
+		var value string
+		if input.Indexed {
+			value, indexedValues = indexedValues[0], indexedValues[1:]
+		} else {
+			value, nonIndexedValues = FormatAbiValue(nonIndexedValues[0]), nonIndexedValues[1:]
+		}
+		arguments = append(arguments, core.EventArgument{
+			Name:    input.Name,
+			Type:    input.Type.String(),
+			Indexed: input.Indexed,
+			Value:   value,
+		})
+	}
+	return arguments, nil
+}
+
+// Indexed values of dynamic types (strings, bytes and arrays) are stored in
+// topics as their keccak hash, so only the hash can be recovered.
+func decodeIndexedValues(indexedInputs []abi.Argument, topics map[int]string) ([]string, error) {
+	var values []string
+	for i, input := range indexedInputs {
+		topic, ok := topics[i+1]
+		if !ok || topic == "" {
+			return nil, ErrInvalidLogData
+		}
+		if isHashedInTopic(input.Type) {
+			values = append(values, topic)
+			continue
+		}
+		unpacked, err := unpackArguments([]abi.Argument{input}, common.HexToHash(topic).Bytes())
+		if err != nil {
+			return nil, ErrInvalidLogData
+		}
+		values = append(values, FormatAbiValue(unpacked[0]))
+	}
+	return values, nil
+}
+
+func isHashedInTopic(argumentType abi.Type) bool {
+	switch argumentType.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+		return true
+	}
+	return false
+}
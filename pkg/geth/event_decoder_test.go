@@ -0,0 +1,103 @@
+package geth_test
+
+import (
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/testing"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decoding logs into events", func() {
+
+	var contractAbi abi.ABI
+	var transferLog core.Log
+
+	BeforeEach(func() {
+		var err error
+		contractAbi, err = geth.ParseAbi(testing.SampleContract().Abi)
+		Expect(err).ToNot(HaveOccurred())
+		transferLog = core.Log{
+			BlockNumber: 4703824,
+			Index:       19,
+			Address:     "0xd26114cd6ee289accf82350c8d8487fedb8a0c07",
+			TxHash:      "0xf896bfd1eb539d881a1a31102b78de9f25cd591bf1fe1924b86148c0b205fd5d",
+			Topics: map[int]string{
+				0: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				1: "0x000000000000000000000000fbb1b73c4f0bda4f67dca266ce6ef42f520fbb98",
+				2: "0x000000000000000000000000d26114cd6ee289accf82350c8d8487fedb8a0c07",
+			},
+			Data: "0x0000000000000000000000000000000000000000000000000c7d713b49da0000",
+		}
+	})
+
+	It("decodes the indexed and non-indexed arguments of a log", func() {
+		decodedEvent, err := geth.DecodeLog(contractAbi, transferLog)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodedEvent).To(Equal(core.DecodedEvent{
+			Address:     transferLog.Address,
+			BlockNumber: 4703824,
+			LogIndex:    19,
+			TxHash:      transferLog.TxHash,
+			Name:        "Transfer",
+			Arguments: []core.EventArgument{
+				{Name: "from", Type: "address", Indexed: true, Value: "0xFBb1b73C4f0BDa4f67dcA266ce6Ef42f520fBB98"},
+				{Name: "to", Type: "address", Indexed: true, Value: "0xd26114cd6EE289AccF82350c8d8487fedB8A0C07"},
+				{Name: "value", Type: "uint256", Indexed: false, Value: "900000000000000000"},
+			},
+		}))
+	})
+
+	It("returns an error when no event in the abi matches the log", func() {
+		transferLog.Topics[0] = "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+		_, err := geth.DecodeLog(contractAbi, transferLog)
+
+		Expect(err).To(Equal(geth.ErrNoMatchingEvent))
+	})
+
+	It("returns an error when the log is missing an indexed argument", func() {
+		delete(transferLog.Topics, 2)
+
+		_, err := geth.DecodeLog(contractAbi, transferLog)
+
+		Expect(err).To(Equal(geth.ErrInvalidLogData))
+	})
+
+	It("decodes several non-indexed arguments and keeps hashed indexed arguments as the topic", func() {
+		eventAbi, err := geth.ParseAbi(`[{"anonymous":false,"type":"event","name":"Registered",
+			"inputs":[{"indexed":true,"name":"label","type":"string"},
+			          {"indexed":false,"name":"owner","type":"address"},
+			          {"indexed":false,"name":"active","type":"bool"}]}]`)
+		Expect(err).ToNot(HaveOccurred())
+		log := core.Log{
+			Topics: map[int]string{
+				0: eventAbi.Events["Registered"].Id().Hex(),
+				1: "0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8",
+			},
+			Data: "0x000000000000000000000000fbb1b73c4f0bda4f67dca266ce6ef42f520fbb98" +
+				"0000000000000000000000000000000000000000000000000000000000000001",
+		}
+
+		decodedEvent, err := geth.DecodeLog(eventAbi, log)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodedEvent.Arguments).To(Equal([]core.EventArgument{
+			{Name: "label", Type: "string", Indexed: true, Value: "0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8"},
+			{Name: "owner", Type: "address", Indexed: false, Value: "0xFBb1b73C4f0BDa4f67dcA266ce6Ef42f520fBB98"},
+			{Name: "active", Type: "bool", Indexed: false, Value: "true"},
+		}))
+	})
+
+	It("skips logs that cannot be decoded", func() {
+		unknownLog := transferLog
+		unknownLog.Topics = map[int]string{0: "0x01"}
+
+		decodedEvents := geth.DecodeLogs(contractAbi, []core.Log{unknownLog, transferLog})
+
+		Expect(len(decodedEvents)).To(Equal(1))
+		Expect(decodedEvents[0].Name).To(Equal("Transfer"))
+	})
+})
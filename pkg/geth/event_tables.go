@@ -0,0 +1,77 @@
+package geth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+// EventTables describes one table per event in the contract abi, named
+// <prefix>_<event_name> with a column for each event argument.
+func EventTables(prefix string, contract core.Contract) ([]core.EventTable, error) {
+	parsed, err := ParseAbi(contract.Abi)
+	if err != nil {
+		return nil, err
+	}
+	var eventTables []core.EventTable
+	for _, event := range parsed.Events {
+		if event.Anonymous {
+			continue
+		}
+		var columns []core.EventTableColumn
+		taken := make(map[string]bool)
+		for _, name := range core.EventTableLogColumns {
+			taken[name] = true
+		}
+		for i, input := range event.Inputs {
+			name := columnName(input.Name, i)
+			// an argument named like a log column or like an earlier
+			// argument, e.g. blockNumber, gets an arg_ prefix
+			for taken[name] {
+				name = "arg_" + name
+			}
+			taken[name] = true
+			columns = append(columns, core.EventTableColumn{
+				Name:    name,
+				Type:    input.Type.String(),
+				Indexed: input.Indexed,
+			})
+		}
+		eventTables = append(eventTables, core.EventTable{
+			Name:         fmt.Sprintf("%s_%s", prefix, toSnakeCase(event.Name)),
+			ContractHash: contract.Hash,
+			EventName:    event.Name,
+			Columns:      columns,
+		})
+	}
+	sort.Slice(eventTables, func(i, j int) bool {
+		return eventTables[i].Name < eventTables[j].Name
+	})
+	return eventTables, nil
+}
+
+func columnName(argumentName string, position int) string {
+	name := strings.TrimLeft(argumentName, "_")
+	if name == "" {
+		return fmt.Sprintf("arg%d", position)
+	}
+	return toSnakeCase(name)
+}
+
+func toSnakeCase(name string) string {
+	var snakeCase []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				snakeCase = append(snakeCase, '_')
+			}
+			r = unicode.ToLower(r)
+		}
+		snakeCase = append(snakeCase, r)
+	}
+	return string(snakeCase)
+}
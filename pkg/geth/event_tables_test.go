@@ -0,0 +1,83 @@
+package geth_test
+
+import (
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/testing"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Event tables", func() {
+
+	It("describes a table for each event in the contract abi", func() {
+		contract := testing.SampleContract()
+
+		eventTables, err := geth.EventTables("token", contract)
+
+		Expect(err).ToNot(HaveOccurred())
+		var tableNames []string
+		for _, eventTable := range eventTables {
+			tableNames = append(tableNames, eventTable.Name)
+		}
+		Expect(tableNames).To(ContainElement("token_transfer"))
+		Expect(tableNames).To(ContainElement("token_approval"))
+		Expect(tableNames).To(ContainElement("token_mint_finished"))
+	})
+
+	It("has a column for each event argument", func() {
+		contract := testing.SampleContract()
+
+		eventTables, _ := geth.EventTables("token", contract)
+
+		var transferTable core.EventTable
+		for _, eventTable := range eventTables {
+			if eventTable.EventName == "Transfer" {
+				transferTable = eventTable
+			}
+		}
+		Expect(transferTable.Name).To(Equal("token_transfer"))
+		Expect(transferTable.ContractHash).To(Equal(contract.Hash))
+		Expect(transferTable.Columns).To(Equal([]core.EventTableColumn{
+			{Name: "from", Type: "address", Indexed: true},
+			{Name: "to", Type: "address", Indexed: true},
+			{Name: "value", Type: "uint256", Indexed: false},
+		}))
+	})
+
+	It("names columns for unnamed and underscored arguments", func() {
+		contract := core.Contract{Abi: `[{"anonymous":false,"type":"event","name":"OwnershipTransferred",
+			"inputs":[{"indexed":true,"name":"_previousOwner","type":"address"},
+			          {"indexed":false,"name":"","type":"uint256"}]}]`}
+
+		eventTables, err := geth.EventTables("token", contract)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(eventTables[0].Name).To(Equal("token_ownership_transferred"))
+		Expect(eventTables[0].Columns[0].Name).To(Equal("previous_owner"))
+		Expect(eventTables[0].Columns[1].Name).To(Equal("arg1"))
+	})
+
+	It("renames arguments that collide with the log columns or each other", func() {
+		contract := core.Contract{Abi: `[{"anonymous":false,"type":"event","name":"Checkpoint",
+			"inputs":[{"indexed":false,"name":"blockNumber","type":"uint256"},
+			          {"indexed":false,"name":"log_id","type":"uint256"},
+			          {"indexed":false,"name":"fooBar","type":"uint256"},
+			          {"indexed":false,"name":"foo_bar","type":"uint256"}]}]`}
+
+		eventTables, err := geth.EventTables("token", contract)
+
+		Expect(err).ToNot(HaveOccurred())
+		var columnNames []string
+		for _, column := range eventTables[0].Columns {
+			columnNames = append(columnNames, column.Name)
+		}
+		Expect(columnNames).To(Equal([]string{"arg_block_number", "arg_log_id", "foo_bar", "arg_foo_bar"}))
+	})
+
+	It("returns an error for an invalid abi", func() {
+		_, err := geth.EventTables("token", core.Contract{Abi: "invalid"})
+
+		Expect(err).To(Equal(geth.ErrInvalidAbiFile))
+	})
+})
@@ -1,28 +1,50 @@
 package geth
 
 import (
+	"context"
+	"math/big"
 	"strings"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"golang.org/x/net/context"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type GethClient interface {
 	TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
 }
 
-func GethBlockToCoreBlock(gethBlock *types.Block, client GethClient) core.Block {
+// GethBlockToCoreBlock converts the block with the senders, receipts and
+// created contracts' code retrieved from the client, failing if any of them
+// cannot be retrieved.
+func GethBlockToCoreBlock(ctx context.Context, gethBlock *types.Block, client GethClient) (core.Block, error) {
 	transactions := []core.Transaction{}
 	for i, gethTransaction := range gethBlock.Transactions() {
-		from, _ := client.TransactionSender(context.Background(), gethTransaction, gethBlock.Hash(), uint(i))
+		from, err := client.TransactionSender(ctx, gethTransaction, gethBlock.Hash(), uint(i))
+		if err != nil {
+			return core.Block{}, err
+		}
 		transaction := gethTransToCoreTrans(gethTransaction, &from)
+		transaction, err = appendReceiptToTransaction(ctx, client, transaction)
+		if err != nil {
+			return core.Block{}, err
+		}
+		if transaction.CreatesContract() {
+			transaction.Receipt.ContractCodeHash, err = contractCodeHash(ctx, client, transaction.Receipt.ContractAddress, gethBlock.Number())
+			if err != nil {
+				return core.Block{}, err
+			}
+		}
 		transactions = append(transactions, transaction)
 	}
 	return core.Block{
-		Difficulty:   gethBlock.Difficulty().Int64(),
+		Difficulty:   gethBlock.Difficulty(),
 		GasLimit:     gethBlock.GasLimit().Int64(),
 		GasUsed:      gethBlock.GasUsed().Int64(),
 		Hash:         gethBlock.Hash().Hex(),
@@ -33,7 +55,46 @@ func GethBlockToCoreBlock(gethBlock *types.Block, client GethClient) core.Block
 		Time:         gethBlock.Time().Int64(),
 		Transactions: transactions,
 		UncleHash:    gethBlock.UncleHash().Hex(),
+	}, nil
+}
+
+// appendReceiptToTransaction leaves the receipt empty when the node does not
+// have one, but fails when the receipt cannot be retrieved.
+func appendReceiptToTransaction(ctx context.Context, client GethClient, transaction core.Transaction) (core.Transaction, error) {
+	gethReceipt, err := client.TransactionReceipt(ctx, common.HexToHash(transaction.Hash))
+	if err == ethereum.NotFound {
+		return transaction, nil
+	}
+	if err != nil {
+		return transaction, err
+	}
+	if gethReceipt == nil {
+		return transaction, nil
+	}
+	transaction.Receipt = GethReceiptToCoreReceipt(gethReceipt)
+	return transaction, nil
+}
+
+// contractCodeHash returns the hash of the code at the address after the
+// block, or an empty string if no code is left or the node answers with an
+// error, as a node that has pruned the state of the block does. Failing to
+// reach the node is returned.
+func contractCodeHash(ctx context.Context, client GethClient, contractAddress string, blockNumber *big.Int) (string, error) {
+	code, err := client.CodeAt(ctx, common.HexToAddress(contractAddress), blockNumber)
+	if err != nil && !isNodeError(err) {
+		return "", err
+	}
+	if len(code) == 0 {
+		return "", nil
 	}
+	return crypto.Keccak256Hash(code).Hex(), nil
+}
+
+// isNodeError is true when the node answered the call with an error, rather
+// than the call failing to reach it.
+func isNodeError(err error) bool {
+	_, ok := err.(rpc.Error)
+	return ok
 }
 
 func gethTransToCoreTrans(transaction *types.Transaction, from *common.Address) core.Transaction {
@@ -44,8 +105,8 @@ func gethTransToCoreTrans(transaction *types.Transaction, from *common.Address)
 		To:       strings.ToLower(addressToHex(transaction.To())),
 		From:     strings.ToLower(addressToHex(from)),
 		GasLimit: transaction.Gas().Int64(),
-		GasPrice: transaction.GasPrice().Int64(),
-		Value:    transaction.Value().Int64(),
+		GasPrice: transaction.GasPrice(),
+		Value:    transaction.Value(),
 	}
 }
 
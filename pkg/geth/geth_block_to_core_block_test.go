@@ -1,24 +1,68 @@
 package geth_test
 
 import (
-	"math/big"
-
 	"context"
+	"errors"
+	"math/big"
 
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
-type FakeGethClient struct{}
+type FakeGethClient struct {
+	receipts   map[string]*types.Receipt
+	code       map[common.Address][]byte
+	senderErr  error
+	receiptErr error
+	codeErr    error
+}
 
 func (client *FakeGethClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	if client.senderErr != nil {
+		return common.Address{}, client.senderErr
+	}
 	return common.HexToAddress("0x123"), nil
 }
 
+func (client *FakeGethClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if client.receiptErr != nil {
+		return nil, client.receiptErr
+	}
+	return client.receipts[txHash.Hex()], nil
+}
+
+func (client *FakeGethClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	if client.codeErr != nil {
+		return nil, client.codeErr
+	}
+	return client.code[account], nil
+}
+
+func (client *FakeGethClient) SetCode(account common.Address, code []byte) {
+	client.code = map[common.Address][]byte{account: code}
+}
+
+// nodeError is an error answered by the node, like one for state it has pruned.
+type nodeError struct{}
+
+func (nodeError) Error() string { return "missing trie node" }
+
+func (nodeError) ErrorCode() int { return -32000 }
+
+func (client *FakeGethClient) AddReceipts(receipts []*types.Receipt) {
+	client.receipts = make(map[string]*types.Receipt)
+	for _, receipt := range receipts {
+		client.receipts[receipt.TxHash.Hex()] = receipt
+	}
+}
+
 var _ = Describe("Conversion of GethBlock to core.Block", func() {
 
 	It("converts basic Block metada", func() {
@@ -41,9 +85,10 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 		}
 		block := types.NewBlock(&header, []*types.Transaction{}, []*types.Header{}, []*types.Receipt{})
 		client := &FakeGethClient{}
-		gethBlock := geth.GethBlockToCoreBlock(block, client)
+		gethBlock, err := geth.GethBlockToCoreBlock(context.Background(), block, client)
 
-		Expect(gethBlock.Difficulty).To(Equal(difficulty.Int64()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gethBlock.Difficulty).To(Equal(difficulty))
 		Expect(gethBlock.GasLimit).To(Equal(gasLimit))
 		Expect(gethBlock.GasUsed).To(Equal(gasUsed))
 		Expect(gethBlock.Hash).To(Equal(block.Hash().Hex()))
@@ -61,8 +106,9 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			header := types.Header{}
 			block := types.NewBlock(&header, []*types.Transaction{}, []*types.Header{}, []*types.Receipt{})
 			client := &FakeGethClient{}
-			coreBlock := geth.GethBlockToCoreBlock(block, client)
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), block, client)
 
+			Expect(err).NotTo(HaveOccurred())
 			Expect(len(coreBlock.Transactions)).To(Equal(0))
 		})
 
@@ -78,16 +124,17 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			gethTransaction := types.NewTransaction(nonce, to, amount, gasLimit, gasPrice, payload)
 			client := &FakeGethClient{}
 			gethBlock := types.NewBlock(&header, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{})
-			coreBlock := geth.GethBlockToCoreBlock(gethBlock, client)
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
 
+			Expect(err).NotTo(HaveOccurred())
 			Expect(len(coreBlock.Transactions)).To(Equal(1))
 			coreTransaction := coreBlock.Transactions[0]
 			Expect(coreTransaction.Data).To(Equal(gethTransaction.Data()))
 			Expect(coreTransaction.To).To(Equal(gethTransaction.To().Hex()))
 			Expect(coreTransaction.From).To(Equal("0x0000000000000000000000000000000000000123"))
 			Expect(coreTransaction.GasLimit).To(Equal(gethTransaction.Gas().Int64()))
-			Expect(coreTransaction.GasPrice).To(Equal(gethTransaction.GasPrice().Int64()))
-			Expect(coreTransaction.Value).To(Equal(gethTransaction.Value().Int64()))
+			Expect(coreTransaction.GasPrice).To(Equal(gethTransaction.GasPrice()))
+			Expect(coreTransaction.Value).To(Equal(gethTransaction.Value()))
 			Expect(coreTransaction.Nonce).To(Equal(gethTransaction.Nonce()))
 		})
 
@@ -96,11 +143,153 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			gethBlock := types.NewBlock(&types.Header{}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{})
 			client := &FakeGethClient{}
 
-			coreBlock := geth.GethBlockToCoreBlock(gethBlock, client)
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
 
+			Expect(err).NotTo(HaveOccurred())
 			coreTransaction := coreBlock.Transactions[0]
 			Expect(coreTransaction.To).To(Equal(""))
 		})
+
+		It("includes the receipt of the transaction", func() {
+			gethTransaction := types.NewTransaction(uint64(10000), common.Address{1}, big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethReceipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: big.NewInt(7996119),
+				GasUsed:           big.NewInt(21000),
+				TxHash:            gethTransaction.Hash(),
+			}
+			gethBlock := types.NewBlock(&types.Header{}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{gethReceipt})
+			client := &FakeGethClient{}
+			client.AddReceipts([]*types.Receipt{gethReceipt})
+
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			coreTransaction := coreBlock.Transactions[0]
+			Expect(coreTransaction.Receipt).To(Equal(geth.GethReceiptToCoreReceipt(gethReceipt)))
+		})
+
+		It("includes the hash of the code of the contract created", func() {
+			gethTransaction := types.NewContractCreation(uint64(10000), big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			contractAddress := common.HexToAddress("0xabc")
+			gethReceipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: big.NewInt(100000),
+				GasUsed:           big.NewInt(100000),
+				ContractAddress:   contractAddress,
+				TxHash:            gethTransaction.Hash(),
+			}
+			gethBlock := types.NewBlock(&types.Header{Number: big.NewInt(5)}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{gethReceipt})
+			client := &FakeGethClient{}
+			client.AddReceipts([]*types.Receipt{gethReceipt})
+			client.SetCode(contractAddress, []byte{0x60, 0x60})
+
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			coreTransaction := coreBlock.Transactions[0]
+			Expect(coreTransaction.CreatesContract()).To(BeTrue())
+			Expect(coreTransaction.Receipt.ContractCodeHash).To(Equal(crypto.Keccak256Hash([]byte{0x60, 0x60}).Hex()))
+		})
+
+		It("leaves out the code hash when the node has no code for the contract", func() {
+			gethTransaction := types.NewContractCreation(uint64(10000), big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethReceipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: big.NewInt(100000),
+				GasUsed:           big.NewInt(100000),
+				ContractAddress:   common.HexToAddress("0xabc"),
+				TxHash:            gethTransaction.Hash(),
+			}
+			gethBlock := types.NewBlock(&types.Header{Number: big.NewInt(5)}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{gethReceipt})
+			client := &FakeGethClient{}
+			client.AddReceipts([]*types.Receipt{gethReceipt})
+
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coreBlock.Transactions[0].Receipt.ContractCodeHash).To(BeEmpty())
+		})
+
+		It("leaves out the code hash when the node answers with an error for the code", func() {
+			gethTransaction := types.NewContractCreation(uint64(10000), big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethReceipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: big.NewInt(100000),
+				GasUsed:           big.NewInt(100000),
+				ContractAddress:   common.HexToAddress("0xabc"),
+				TxHash:            gethTransaction.Hash(),
+			}
+			gethBlock := types.NewBlock(&types.Header{Number: big.NewInt(5)}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{gethReceipt})
+			client := &FakeGethClient{codeErr: nodeError{}}
+			client.AddReceipts([]*types.Receipt{gethReceipt})
+
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coreBlock.Transactions[0].Receipt.ContractCodeHash).To(BeEmpty())
+		})
+
+		It("returns an error when the code of the contract created cannot be retrieved", func() {
+			gethTransaction := types.NewContractCreation(uint64(10000), big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethReceipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: big.NewInt(100000),
+				GasUsed:           big.NewInt(100000),
+				ContractAddress:   common.HexToAddress("0xabc"),
+				TxHash:            gethTransaction.Hash(),
+			}
+			gethBlock := types.NewBlock(&types.Header{Number: big.NewInt(5)}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{gethReceipt})
+			client := &FakeGethClient{codeErr: errors.New("connection refused")}
+			client.AddReceipts([]*types.Receipt{gethReceipt})
+
+			_, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).To(MatchError("connection refused"))
+		})
+
+		It("has an empty receipt when the node does not find one", func() {
+			gethTransaction := types.NewTransaction(uint64(10000), common.Address{1}, big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethBlock := types.NewBlock(&types.Header{}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{})
+			client := &FakeGethClient{receiptErr: ethereum.NotFound}
+
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coreBlock.Transactions[0].Receipt).To(Equal(core.Receipt{}))
+		})
+
+		It("returns an error when the receipt cannot be retrieved", func() {
+			gethTransaction := types.NewTransaction(uint64(10000), common.Address{1}, big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethBlock := types.NewBlock(&types.Header{}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{})
+			client := &FakeGethClient{receiptErr: errors.New("connection refused")}
+
+			_, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).To(MatchError("connection refused"))
+		})
+
+		It("returns an error when the sender cannot be retrieved", func() {
+			gethTransaction := types.NewTransaction(uint64(10000), common.Address{1}, big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethBlock := types.NewBlock(&types.Header{}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{})
+			client := &FakeGethClient{senderErr: errors.New("connection refused")}
+
+			_, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).To(MatchError("connection refused"))
+		})
+
+		It("has an empty receipt when the node does not return one", func() {
+			gethTransaction := types.NewTransaction(uint64(10000), common.Address{1}, big.NewInt(10), big.NewInt(5000), big.NewInt(3), []byte("1234"))
+			gethBlock := types.NewBlock(&types.Header{}, []*types.Transaction{gethTransaction}, []*types.Header{}, []*types.Receipt{})
+			client := &FakeGethClient{}
+
+			coreBlock, err := geth.GethBlockToCoreBlock(context.Background(), gethBlock, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			coreTransaction := coreBlock.Transactions[0]
+			Expect(coreTransaction.Receipt).To(Equal(core.Receipt{}))
+		})
 	})
 
 })
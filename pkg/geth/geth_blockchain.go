@@ -1,39 +1,41 @@
 package geth
 
 import (
+	"context"
 	"math/big"
 
 	"log"
 
+	"github.com/vulcanize/vulcanizedb/pkg/config"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/node"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
-	"golang.org/x/net/context"
 )
 
 type GethBlockchain struct {
-	client              *ethclient.Client
+	client              Client
+	batchClient         BatchClient
 	readGethHeaders     chan *types.Header
 	outputBlocks        chan core.Block
 	newHeadSubscription ethereum.Subscription
-	node                core.Node
+	// poller replaces the new head subscription for nodes reached over HTTP
+	poller *BlockPoller
+	node   core.Node
 }
 
-func (blockchain *GethBlockchain) GetLogs(contract core.Contract, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ([]core.Log, error) {
+func (blockchain *GethBlockchain) GetLogs(ctx context.Context, contract core.Contract, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ([]core.Log, error) {
+	return blockchain.FilterLogs(ctx, core.LogFilter{Addresses: []string{contract.Hash}}, startingBlockNumber, endingBlockNumber)
+}
+
+func (blockchain *GethBlockchain) FilterLogs(ctx context.Context, filter core.LogFilter, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ([]core.Log, error) {
 	if endingBlockNumber == nil {
 		endingBlockNumber = startingBlockNumber
 	}
-	contractAddress := common.HexToAddress(contract.Hash)
-	fc := ethereum.FilterQuery{
-		FromBlock: startingBlockNumber,
-		ToBlock:   endingBlockNumber,
-		Addresses: []common.Address{contractAddress},
-	}
-	gethLogs, err := blockchain.client.FilterLogs(context.Background(), fc)
+	fc := CoreLogFilterToFilterQuery(filter, startingBlockNumber, endingBlockNumber)
+	gethLogs, err := blockchain.client.FilterLogs(ctx, fc)
 	if err != nil {
 		return []core.Log{}, err
 	}
@@ -41,46 +43,114 @@ func (blockchain *GethBlockchain) GetLogs(contract core.Contract, startingBlockN
 	return logs, nil
 }
 
+// FindDeploymentBlock returns the number of the block the contract was
+// created in, looking in the saved receipts first.
+func (blockchain *GethBlockchain) FindDeploymentBlock(ctx context.Context, receipts DeploymentReceipts, contractHash string) (int64, error) {
+	lastBlock, err := blockchain.LastBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return FindDeploymentBlock(ctx, blockchain.client, receipts, contractHash, lastBlock.Int64())
+}
+
 func (blockchain *GethBlockchain) Node() core.Node {
 	return blockchain.node
 }
 
-func (blockchain *GethBlockchain) GetBlockByNumber(blockNumber int64) core.Block {
-	gethBlock, _ := blockchain.client.BlockByNumber(context.Background(), big.NewInt(blockNumber))
-	return GethBlockToCoreBlock(gethBlock, blockchain.client)
+func (blockchain *GethBlockchain) GetBlockByNumber(ctx context.Context, blockNumber int64) (core.Block, error) {
+	gethBlock, err := blockchain.client.BlockByNumber(ctx, big.NewInt(blockNumber))
+	if err != nil {
+		return core.Block{}, err
+	}
+	return GethBlockToCoreBlock(ctx, gethBlock, blockchain.client)
 }
 
-func NewGethBlockchain(ipcPath string) *GethBlockchain {
+// NewGethBlockchain connects to the node at the client's IPC path or URL.
+// New blocks are polled for when the node is reached over HTTP, and
+// subscribed to otherwise.
+func NewGethBlockchain(clientConfig config.Client) (*GethBlockchain, error) {
 	blockchain := GethBlockchain{}
-	rpcClient, _ := rpc.Dial(ipcPath)
+	rpcClient, err := rpc.Dial(clientConfig.IPCPath)
+	if err != nil {
+		return nil, err
+	}
 	client := ethclient.NewClient(rpcClient)
 	blockchain.node = node.Retrieve(rpcClient)
 	blockchain.client = client
-	return &blockchain
+	blockchain.batchClient = rpcClient
+	if clientConfig.UsesHTTP() {
+		blockchain.poller = NewBlockPoller(&blockchain, clientConfig.BlockPollingInterval())
+	}
+	return &blockchain, nil
+}
+
+// LimitRate keeps the calls made to the node under callsPerSecond, counting
+// each call in a batch, where 0 is unlimited.
+func (blockchain *GethBlockchain) LimitRate(callsPerSecond int) {
+	if callsPerSecond > 0 {
+		limiter := NewRateLimiter(callsPerSecond)
+		blockchain.client = NewRateLimitedClient(blockchain.client, limiter)
+		blockchain.batchClient = NewRateLimitedBatchClient(blockchain.batchClient, limiter)
+	}
 }
 
-func (blockchain *GethBlockchain) SubscribeToBlocks(blocks chan core.Block) {
+func (blockchain *GethBlockchain) SubscribeToBlocks(ctx context.Context, blocks chan core.Block) error {
+	if blockchain.poller != nil {
+		log.Println("Polling for new blocks")
+		return blockchain.poller.Subscribe(ctx, blocks)
+	}
 	blockchain.outputBlocks = blocks
 	log.Println("SubscribeToBlocks")
 	inputHeaders := make(chan *types.Header, 10)
-	myContext := context.Background()
 	blockchain.readGethHeaders = inputHeaders
-	subscription, _ := blockchain.client.SubscribeNewHead(myContext, inputHeaders)
+	subscription, err := blockchain.client.SubscribeNewHead(ctx, inputHeaders)
+	if err != nil {
+		return err
+	}
 	blockchain.newHeadSubscription = subscription
+	return nil
 }
 
-func (blockchain *GethBlockchain) StartListening() {
-	for header := range blockchain.readGethHeaders {
-		block := blockchain.GetBlockByNumber(header.Number.Int64())
-		blockchain.outputBlocks <- block
+// StartListening sends each new block to the subscribed channel until the
+// context is done or the subscription fails. A block that cannot be retrieved
+// is skipped, to be filled in by the backfill.
+func (blockchain *GethBlockchain) StartListening(ctx context.Context) error {
+	if blockchain.poller != nil {
+		return blockchain.poller.Listen(ctx)
+	}
+	for {
+		select {
+		case header := <-blockchain.readGethHeaders:
+			block, err := blockchain.GetBlockByNumber(ctx, header.Number.Int64())
+			if err != nil {
+				log.Printf("Error retrieving block %d: %v\n", header.Number.Int64(), err)
+				continue
+			}
+			select {
+			case blockchain.outputBlocks <- block:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case err := <-blockchain.newHeadSubscription.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 }
 
 func (blockchain *GethBlockchain) StopListening() {
+	if blockchain.poller != nil {
+		blockchain.poller.Stop()
+		return
+	}
 	blockchain.newHeadSubscription.Unsubscribe()
 }
 
-func (blockchain *GethBlockchain) LastBlock() *big.Int {
-	block, _ := blockchain.client.HeaderByNumber(context.Background(), nil)
-	return block.Number
+func (blockchain *GethBlockchain) LastBlock(ctx context.Context) (*big.Int, error) {
+	header, err := blockchain.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return header.Number, nil
 }
@@ -0,0 +1,48 @@
+package geth
+
+import (
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Receipts created before Byzantium carry a post-transaction state root
+// instead of a status, so whether they succeeded cannot be known.
+const ReceiptStatusUnknown = -1
+
+func GethReceiptToCoreReceipt(gethReceipt *types.Receipt) core.Receipt {
+	stateRoot, status := postStateOrStatus(gethReceipt)
+	return core.Receipt{
+		Bloom:             hexutil.Encode(gethReceipt.Bloom.Bytes()),
+		ContractAddress:   contractAddress(gethReceipt),
+		CumulativeGasUsed: gethReceipt.CumulativeGasUsed.Int64(),
+		GasUsed:           gethReceipt.GasUsed.Int64(),
+		Logs:              receiptLogs(gethReceipt),
+		StateRoot:         stateRoot,
+		Status:            status,
+		TxHash:            gethReceipt.TxHash.Hex(),
+	}
+}
+
+func postStateOrStatus(gethReceipt *types.Receipt) (string, int) {
+	if len(gethReceipt.PostState) != 0 {
+		return hexutil.Encode(gethReceipt.PostState), ReceiptStatusUnknown
+	}
+	return "", int(gethReceipt.Status)
+}
+
+func contractAddress(gethReceipt *types.Receipt) string {
+	if gethReceipt.ContractAddress == (common.Address{}) {
+		return ""
+	}
+	return gethReceipt.ContractAddress.Hex()
+}
+
+func receiptLogs(gethReceipt *types.Receipt) []core.Log {
+	var logs []core.Log
+	for _, gethLog := range gethReceipt.Logs {
+		logs = append(logs, GethLogToCoreLog(*gethLog))
+	}
+	return logs
+}
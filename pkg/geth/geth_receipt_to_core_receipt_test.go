@@ -0,0 +1,90 @@
+package geth_test
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Conversion of GethReceipt to core.Receipt", func() {
+
+	It(`converts geth receipt to internal receipt format (pre Byzantium has post state root)`, func() {
+		receipt := types.Receipt{
+			Bloom:             types.Bloom{},
+			ContractAddress:   common.Address{},
+			CumulativeGasUsed: big.NewInt(25000),
+			GasUsed:           big.NewInt(21000),
+			Logs:              []*types.Log{},
+			PostState:         hexutil.MustDecode("0x88abf7e73128227370aa7baa3dd4e18d0af70e92ef1f9ef426942fbe2dddb733"),
+			TxHash:            common.HexToHash("0x97d99bc7729211111a21b12c933c949d4f31684f1d6954ff477d0477538ff017"),
+		}
+
+		expected := core.Receipt{
+			Bloom:             "0x" + strings.Repeat("0", 512),
+			ContractAddress:   "",
+			CumulativeGasUsed: 25000,
+			GasUsed:           21000,
+			Logs:              nil,
+			StateRoot:         "0x88abf7e73128227370aa7baa3dd4e18d0af70e92ef1f9ef426942fbe2dddb733",
+			Status:            geth.ReceiptStatusUnknown,
+			TxHash:            receipt.TxHash.Hex(),
+		}
+
+		coreReceipt := geth.GethReceiptToCoreReceipt(&receipt)
+		Expect(coreReceipt).To(Equal(expected))
+	})
+
+	It("converts geth receipt to internal receipt format (post Byzantium has status)", func() {
+		receipt := types.Receipt{
+			ContractAddress:   common.HexToAddress("0x0cbd6dea8c2fb3fd2e56cb6f23c1e8e7e1a1d5c6"),
+			CumulativeGasUsed: big.NewInt(7996119),
+			GasUsed:           big.NewInt(21000),
+			Logs:              []*types.Log{},
+			Status:            types.ReceiptStatusFailed,
+			TxHash:            common.HexToHash("0xe340558980f89d5f86045ac11e5cc34e4bcec20f9f1e2a427aa39d87114e8223"),
+		}
+
+		coreReceipt := geth.GethReceiptToCoreReceipt(&receipt)
+
+		Expect(coreReceipt.ContractAddress).To(Equal(receipt.ContractAddress.Hex()))
+		Expect(coreReceipt.CumulativeGasUsed).To(Equal(int64(7996119)))
+		Expect(coreReceipt.GasUsed).To(Equal(int64(21000)))
+		Expect(coreReceipt.StateRoot).To(Equal(""))
+		Expect(coreReceipt.Status).To(Equal(0))
+		Expect(coreReceipt.TxHash).To(Equal(receipt.TxHash.Hex()))
+	})
+
+	It("converts the logs of the receipt", func() {
+		gethLog := &types.Log{
+			Address:     common.HexToAddress("0xecf8f87f810ecf450940c9f60066b4a7a501d6a7"),
+			BlockNumber: 2019236,
+			Data:        hexutil.MustDecode("0x000000000000000000000000000000000000000000000001a055690d9db80000"),
+			Index:       2,
+			TxHash:      common.HexToHash("0x3b198bfd5d2907285af009e9ae84a0ecd63677110d89d7e030251acb87f6487e"),
+			Topics: []common.Hash{
+				common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			},
+		}
+		receipt := types.Receipt{
+			CumulativeGasUsed: big.NewInt(7996119),
+			GasUsed:           big.NewInt(21000),
+			Logs:              []*types.Log{gethLog},
+			Status:            types.ReceiptStatusSuccessful,
+			TxHash:            gethLog.TxHash,
+		}
+
+		coreReceipt := geth.GethReceiptToCoreReceipt(&receipt)
+
+		Expect(coreReceipt.Status).To(Equal(1))
+		Expect(len(coreReceipt.Logs)).To(Equal(1))
+		Expect(coreReceipt.Logs[0]).To(Equal(geth.GethLogToCoreLog(*gethLog)))
+	})
+
+})
@@ -0,0 +1,34 @@
+package geth
+
+import (
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CoreLogFilterToFilterQuery converts the filter for the blocks in range.
+// Topic values shorter than 32 bytes, such as addresses, are left-padded as
+// they are when indexed.
+func CoreLogFilterToFilterQuery(filter core.LogFilter, startingBlockNumber *big.Int, endingBlockNumber *big.Int) ethereum.FilterQuery {
+	query := ethereum.FilterQuery{
+		FromBlock: startingBlockNumber,
+		ToBlock:   endingBlockNumber,
+	}
+	for _, address := range filter.Addresses {
+		query.Addresses = append(query.Addresses, common.HexToAddress(address))
+	}
+	lastTopic := len(filter.Topics) - 1
+	for lastTopic >= 0 && len(filter.Topics[lastTopic]) == 0 {
+		lastTopic--
+	}
+	for _, values := range filter.Topics[:lastTopic+1] {
+		var hashes []common.Hash
+		for _, value := range values {
+			hashes = append(hashes, common.HexToHash(value))
+		}
+		query.Topics = append(query.Topics, hashes)
+	}
+	return query
+}
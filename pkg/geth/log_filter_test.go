@@ -0,0 +1,51 @@
+package geth_test
+
+import (
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Conversion of core.LogFilter to FilterQuery", func() {
+	transferSignature := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+	It("filters by the addresses in the block range", func() {
+		filter := core.LogFilter{Addresses: []string{"0xecf8f87f810ecf450940c9f60066b4a7a501d6a7", "0x123"}}
+
+		query := geth.CoreLogFilterToFilterQuery(filter, big.NewInt(10), big.NewInt(20))
+
+		Expect(query.FromBlock).To(Equal(big.NewInt(10)))
+		Expect(query.ToBlock).To(Equal(big.NewInt(20)))
+		Expect(query.Addresses).To(Equal([]common.Address{
+			common.HexToAddress("0xecf8f87f810ecf450940c9f60066b4a7a501d6a7"),
+			common.HexToAddress("0x123"),
+		}))
+		Expect(query.Topics).To(BeNil())
+	})
+
+	It("matches any address without addresses", func() {
+		filter := core.LogFilter{Topics: [][]string{{transferSignature}}}
+
+		query := geth.CoreLogFilterToFilterQuery(filter, big.NewInt(10), big.NewInt(20))
+
+		Expect(query.Addresses).To(BeNil())
+		Expect(query.Topics).To(Equal([][]common.Hash{{common.HexToHash(transferSignature)}}))
+	})
+
+	It("pads addresses used as topics and leaves topics without values as wildcards", func() {
+		recipient := "0x80b2c9d7cbbf30a1b0fc8983c647d754c6525615"
+		filter := core.LogFilter{Topics: [][]string{{transferSignature}, {}, {recipient}, {}}}
+
+		query := geth.CoreLogFilterToFilterQuery(filter, big.NewInt(10), big.NewInt(20))
+
+		Expect(query.Topics).To(Equal([][]common.Hash{
+			{common.HexToHash(transferSignature)},
+			nil,
+			{common.HexToHash("0x00000000000000000000000080b2c9d7cbbf30a1b0fc8983c647d754c6525615")},
+		}))
+	})
+})
@@ -0,0 +1,142 @@
+package geth
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client is the part of ethclient.Client used by GethBlockchain.
+type Client interface {
+	GethClient
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeNewHead(ctx context.Context, headers chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// RateLimiter spaces requests to the node evenly, reserving a slot for each
+// request so that concurrent callers share the rate.
+type RateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func NewRateLimiter(requestsPerSecond int) *RateLimiter {
+	return &RateLimiter{interval: time.Second / time.Duration(requestsPerSecond)}
+}
+
+// wait returns when the last of count requests is allowed, or with the error
+// of the context if it is done first.
+func (limiter *RateLimiter) wait(ctx context.Context, count int) error {
+	if count == 0 {
+		return nil
+	}
+	limiter.mutex.Lock()
+	now := time.Now()
+	if limiter.next.Before(now) {
+		limiter.next = now
+	}
+	limiter.next = limiter.next.Add(time.Duration(count) * limiter.interval)
+	delay := limiter.next.Sub(now) - limiter.interval
+	limiter.mutex.Unlock()
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewRateLimitedClient makes every request of the client wait for the
+// limiter.
+func NewRateLimitedClient(client Client, limiter *RateLimiter) Client {
+	return rateLimitedClient{client: client, limiter: limiter}
+}
+
+type rateLimitedClient struct {
+	client  Client
+	limiter *RateLimiter
+}
+
+func (client rateLimitedClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.BlockByNumber(ctx, number)
+}
+
+func (client rateLimitedClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.HeaderByNumber(ctx, number)
+}
+
+func (client rateLimitedClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return common.Address{}, err
+	}
+	return client.client.TransactionSender(ctx, tx, block, index)
+}
+
+func (client rateLimitedClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.TransactionReceipt(ctx, txHash)
+}
+
+func (client rateLimitedClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.CodeAt(ctx, account, blockNumber)
+}
+
+func (client rateLimitedClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.CallContract(ctx, msg, blockNumber)
+}
+
+func (client rateLimitedClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.FilterLogs(ctx, query)
+}
+
+func (client rateLimitedClient) SubscribeNewHead(ctx context.Context, headers chan<- *types.Header) (ethereum.Subscription, error) {
+	if err := client.limiter.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return client.client.SubscribeNewHead(ctx, headers)
+}
+
+// NewRateLimitedBatchClient makes every batch of the client wait for the
+// limiter, counting each call in a batch, as providers like Infura do.
+func NewRateLimitedBatchClient(client BatchClient, limiter *RateLimiter) BatchClient {
+	return rateLimitedBatchClient{client: client, limiter: limiter}
+}
+
+type rateLimitedBatchClient struct {
+	client  BatchClient
+	limiter *RateLimiter
+}
+
+func (client rateLimitedBatchClient) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	if err := client.limiter.wait(ctx, len(batch)); err != nil {
+		return err
+	}
+	return client.client.BatchCallContext(ctx, batch)
+}
@@ -0,0 +1,100 @@
+package geth_test
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// headerClient answers only HeaderByNumber, counting the requests.
+type headerClient struct {
+	geth.Client
+	requests int
+}
+
+func (client *headerClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	client.requests++
+	return &types.Header{Number: big.NewInt(1)}, nil
+}
+
+var _ = Describe("Rate limiting requests to the node", func() {
+	var client *headerClient
+	var batchClient *FakeBatchClient
+
+	BeforeEach(func() {
+		client = &headerClient{}
+		batchClient = NewFakeBatchClient()
+	})
+
+	batch := func(size int) []rpc.BatchElem {
+		var elements []rpc.BatchElem
+		for i := 0; i < size; i++ {
+			elements = append(elements, rpc.BatchElem{Method: "eth_blockNumber", Args: []interface{}{i}, Result: new(string)})
+		}
+		return elements
+	}
+
+	It("spaces the requests of the client", func() {
+		limited := geth.NewRateLimitedClient(client, geth.NewRateLimiter(20))
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			limited.HeaderByNumber(context.Background(), nil)
+		}
+
+		Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+		Expect(client.requests).To(Equal(3))
+	})
+
+	It("shares the rate between the client and batches", func() {
+		limiter := geth.NewRateLimiter(20)
+		limited := geth.NewRateLimitedClient(client, limiter)
+		limitedBatches := geth.NewRateLimitedBatchClient(batchClient, limiter)
+
+		start := time.Now()
+		limited.HeaderByNumber(context.Background(), nil)
+		limitedBatches.BatchCallContext(context.Background(), batch(1))
+
+		Expect(time.Since(start)).To(BeNumerically(">=", 40*time.Millisecond))
+		Expect(batchClient.batchSizes).To(Equal([]int{1}))
+	})
+
+	It("counts each call in a batch against the limit", func() {
+		limited := geth.NewRateLimitedBatchClient(batchClient, geth.NewRateLimiter(50))
+
+		start := time.Now()
+		limited.BatchCallContext(context.Background(), batch(5))
+		limited.BatchCallContext(context.Background(), batch(5))
+
+		Expect(time.Since(start)).To(BeNumerically(">=", 160*time.Millisecond))
+		Expect(batchClient.batchSizes).To(Equal([]int{5, 5}))
+	})
+
+	It("sends a single request without waiting", func() {
+		limited := geth.NewRateLimitedClient(client, geth.NewRateLimiter(1))
+
+		start := time.Now()
+		limited.HeaderByNumber(context.Background(), nil)
+
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("returns without sending the request when the context is done", func() {
+		limiter := geth.NewRateLimiter(1)
+		geth.NewRateLimitedClient(client, limiter).HeaderByNumber(context.Background(), nil)
+		limitedBatches := geth.NewRateLimitedBatchClient(batchClient, limiter)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := limitedBatches.BatchCallContext(ctx, batch(1))
+
+		Expect(err).To(Equal(context.Canceled))
+		Expect(batchClient.batchSizes).To(BeEmpty())
+	})
+})
@@ -0,0 +1,204 @@
+package history
+
+import (
+	"context"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+)
+
+const (
+	// Number of blocks each worker requests from the node at once.
+	blocksPerRequest = 10
+	// Number of fetched requests each worker may hold while earlier blocks
+	// are still being fetched, since blocks are saved in order.
+	requestsAheadPerWorker = 4
+)
+
+type BackfillProgress struct {
+	BlockNumber int64
+	Populated   int
+	Total       int
+}
+
+// Backfiller fetches blocks with a pool of workers and saves them in block
+// number order in batches. The rate of requests to the node is limited by
+// the blockchain, e.g. with GethBlockchain.LimitRate.
+type Backfiller struct {
+	blockchain  core.Blockchain
+	repository  repositories.Repository
+	concurrency int
+	Progress    func(progress BackfillProgress)
+}
+
+// NewBackfiller creates a Backfiller with the number of concurrent workers,
+// each fetching a batch of blocks at a time.
+func NewBackfiller(blockchain core.Blockchain, repository repositories.Repository, concurrency int) Backfiller {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return Backfiller{
+		blockchain:  blockchain,
+		repository:  repository,
+		concurrency: concurrency,
+	}
+}
+
+func (backfiller Backfiller) PopulateMissingBlocks(ctx context.Context, startingBlockNumber int64) int {
+	blockRange := backfiller.repository.MissingBlockNumbers(startingBlockNumber, backfiller.repository.MaxBlockNumber())
+	return backfiller.Backfill(ctx, blockRange)
+}
+
+type fetchedBlock struct {
+	position int
+	block    core.Block
+	err      error
+}
+
+type blockRequest struct {
+	start int
+	end   int
+}
+
+// Backfill returns the number of blocks saved. Blocks that cannot be
+// retrieved from the node or saved are skipped, so they are still missing for
+// the next backfill. When the context is done, no more blocks are requested and
+// the blocks already retrieved in order are saved before returning.
+func (backfiller Backfiller) Backfill(ctx context.Context, blockNumbers []int64) int {
+	requests := make(chan blockRequest)
+	fetched := make(chan fetchedBlock)
+	window := make(chan struct{}, backfiller.concurrency*requestsAheadPerWorker*blocksPerRequest)
+
+	// dispatched receives the number of blocks requested, which is less than
+	// all of them when the context is done first
+	dispatched := make(chan int, 1)
+	go func() {
+		defer close(requests)
+		for start := 0; start < len(blockNumbers); start += blocksPerRequest {
+			end := start + blocksPerRequest
+			if end > len(blockNumbers) {
+				end = len(blockNumbers)
+			}
+			if ctx.Err() != nil || !acquire(ctx, window, end-start) {
+				dispatched <- start
+				return
+			}
+			select {
+			case requests <- blockRequest{start: start, end: end}:
+			case <-ctx.Done():
+				dispatched <- start
+				return
+			}
+		}
+		dispatched <- len(blockNumbers)
+	}()
+	for i := 0; i < backfiller.concurrency; i++ {
+		go func() {
+			for request := range requests {
+				for i, result := range backfiller.fetchBlocks(ctx, blockNumbers[request.start:request.end]) {
+					result.position = request.start + i
+					fetched <- result
+				}
+			}
+		}()
+	}
+
+	pending := make(map[int]fetchedBlock)
+	var unsaved []fetchedBlock
+	saved := 0
+	total := len(blockNumbers)
+	for next := 0; next < total; {
+		select {
+		case result := <-fetched:
+			pending[result.position] = result
+		case total = <-dispatched:
+		}
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if result.err != nil {
+				<-window
+			} else {
+				unsaved = append(unsaved, result)
+			}
+			next++
+		}
+		if len(unsaved) > 0 && (len(unsaved) >= backfiller.blocksPerSave() || next == total) {
+			saved = backfiller.save(unsaved, saved, len(blockNumbers), window)
+			unsaved = nil
+		}
+	}
+	return saved
+}
+
+// acquire reserves room in the window for count blocks, returning false if
+// the context is done first.
+func acquire(ctx context.Context, window chan struct{}, count int) bool {
+	for i := 0; i < count; i++ {
+		select {
+		case window <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// blocksPerSave is the number of in-order blocks written to the repository
+// at once. It is a quarter of the window, so workers keep fetching while a
+// batch is saved.
+func (backfiller Backfiller) blocksPerSave() int {
+	return backfiller.concurrency * blocksPerRequest
+}
+
+// save writes the blocks in one batch and returns the number of blocks saved
+// so far, reporting progress only once the batch is saved.
+func (backfiller Backfiller) save(results []fetchedBlock, saved int, total int, window chan struct{}) int {
+	var blocks []core.Block
+	for _, result := range results {
+		blocks = append(blocks, result.block)
+	}
+	err := saveBlocks(backfiller.repository, blocks)
+	for _, result := range results {
+		<-window
+		if err == nil {
+			saved++
+			backfiller.reportProgress(result.block.Number, saved, total)
+		}
+	}
+	return saved
+}
+
+// fetchBlocks requests the blocks in one batch, falling back to a request
+// per block if the batch fails for any reason other than the context being
+// done.
+func (backfiller Backfiller) fetchBlocks(ctx context.Context, blockNumbers []int64) []fetchedBlock {
+	var results []fetchedBlock
+	blocks, err := backfiller.blockchain.GetBlocksByNumber(ctx, blockNumbers)
+	if err == nil && len(blocks) == len(blockNumbers) {
+		for _, block := range blocks {
+			results = append(results, fetchedBlock{block: block})
+		}
+		return results
+	}
+	if ctx.Err() != nil {
+		for range blockNumbers {
+			results = append(results, fetchedBlock{err: ctx.Err()})
+		}
+		return results
+	}
+	for _, blockNumber := range blockNumbers {
+		block, err := backfiller.blockchain.GetBlockByNumber(ctx, blockNumber)
+		results = append(results, fetchedBlock{block: block, err: err})
+	}
+	return results
+}
+
+func (backfiller Backfiller) reportProgress(blockNumber int64, populated int, total int) {
+	if backfiller.Progress != nil {
+		backfiller.Progress(BackfillProgress{BlockNumber: blockNumber, Populated: populated, Total: total})
+	}
+}
@@ -0,0 +1,127 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// failingRepository fails to save the batches of blocks containing a block.
+type failingRepository struct {
+	*repositories.InMemory
+	failingBlockNumber int64
+}
+
+func (repository failingRepository) CreateOrUpdateBlocks(blocks []core.Block) error {
+	for _, block := range blocks {
+		if block.Number == repository.failingBlockNumber {
+			return errors.New("insert failed")
+		}
+	}
+	return repository.InMemory.CreateOrUpdateBlocks(blocks)
+}
+
+var _ = Describe("Backfilling blocks", func() {
+	var blockchain *fakes.Blockchain
+	var repository *repositories.InMemory
+
+	BeforeEach(func() {
+		var blocks []core.Block
+		for number := int64(1); number <= 20; number++ {
+			blocks = append(blocks, core.Block{Number: number, Hash: fmt.Sprintf("x%d", number)})
+		}
+		blockchain = fakes.NewBlockchainWithBlocks(blocks)
+		repository = repositories.NewInMemory()
+	})
+
+	It("fetches every block with several workers", func() {
+		backfiller := history.NewBackfiller(blockchain, repository, 4)
+
+		populated := backfiller.Backfill(context.Background(), history.MakeRange(1, 21))
+
+		Expect(populated).To(Equal(20))
+		Expect(repository.BlockCount()).To(Equal(20))
+		block, err := repository.FindBlockByNumber(7)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block.Hash).To(Equal("x7"))
+	})
+
+	It("saves the blocks in order and reports progress", func() {
+		var progress []history.BackfillProgress
+		backfiller := history.NewBackfiller(blockchain, repository, 8)
+		backfiller.Progress = func(update history.BackfillProgress) {
+			progress = append(progress, update)
+		}
+
+		backfiller.Backfill(context.Background(), []int64{3, 9, 10, 15})
+
+		Expect(progress).To(Equal([]history.BackfillProgress{
+			{BlockNumber: 3, Populated: 1, Total: 4},
+			{BlockNumber: 9, Populated: 2, Total: 4},
+			{BlockNumber: 10, Populated: 3, Total: 4},
+			{BlockNumber: 15, Populated: 4, Total: 4},
+		}))
+	})
+
+	It("skips the blocks that cannot be retrieved", func() {
+		blockchain.SetBlockError(7, errors.New("connection reset"))
+		backfiller := history.NewBackfiller(blockchain, repository, 4)
+
+		populated := backfiller.Backfill(context.Background(), history.MakeRange(1, 21))
+
+		Expect(populated).To(Equal(19))
+		Expect(repository.BlockCount()).To(Equal(19))
+		Expect(repository.MissingBlockNumbers(1, 20)).To(Equal([]int64{7}))
+	})
+
+	It("does not count or report the blocks that cannot be saved", func() {
+		var progress []history.BackfillProgress
+		backfiller := history.NewBackfiller(blockchain, failingRepository{InMemory: repository, failingBlockNumber: 3}, 1)
+		backfiller.Progress = func(update history.BackfillProgress) {
+			progress = append(progress, update)
+		}
+
+		populated := backfiller.Backfill(context.Background(), history.MakeRange(1, 21))
+
+		Expect(populated).To(Equal(10))
+		Expect(repository.BlockCount()).To(Equal(10))
+		Expect(repository.MissingBlockNumbers(1, 20)).To(Equal(history.MakeRange(1, 11)))
+		Expect(len(progress)).To(Equal(10))
+		Expect(progress[0]).To(Equal(history.BackfillProgress{BlockNumber: 11, Populated: 1, Total: 20}))
+	})
+
+	It("populates the missing blocks from a starting block", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 1})
+		repository.CreateOrUpdateBlock(core.Block{Number: 5})
+		backfiller := history.NewBackfiller(blockchain, repository, 2)
+
+		populated := backfiller.PopulateMissingBlocks(context.Background(), 2)
+
+		Expect(populated).To(Equal(3))
+		Expect(repository.MissingBlockNumbers(1, 5)).To(BeEmpty())
+	})
+
+	It("stops requesting blocks when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		backfiller := history.NewBackfiller(blockchain, repository, 4)
+
+		populated := backfiller.Backfill(ctx, history.MakeRange(1, 21))
+
+		Expect(populated).To(Equal(0))
+		Expect(repository.BlockCount()).To(Equal(0))
+	})
+
+	It("does nothing without blocks to fetch", func() {
+		backfiller := history.NewBackfiller(blockchain, repository, 4)
+
+		Expect(backfiller.Backfill(context.Background(), nil)).To(Equal(0))
+	})
+})
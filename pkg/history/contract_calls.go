@@ -0,0 +1,77 @@
+package history
+
+import (
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// DecodeContractCalls decodes the input of the transactions sent to watched
+// contracts with the abi of the contract and saves the method calls. The
+// watched contracts are read once and each abi is parsed once per call.
+func DecodeContractCalls(repository repositories.Repository, transactions []core.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	watchedAbis := make(map[string]string)
+	for _, contract := range repository.FindWatchedContracts() {
+		watchedAbis[strings.ToLower(contract.Hash)] = contract.Abi
+	}
+	contractAbis := make(map[string]*abi.ABI)
+	var decodedCalls []core.DecodedCall
+	for _, transaction := range transactions {
+		to := strings.ToLower(transaction.To)
+		contractAbi, ok := contractAbis[to]
+		if !ok {
+			contractAbi = parseWatchedAbi(watchedAbis, to)
+			contractAbis[to] = contractAbi
+		}
+		if contractAbi == nil {
+			continue
+		}
+		decodedCall, err := geth.DecodeTransaction(*contractAbi, transaction)
+		if err != nil {
+			continue
+		}
+		decodedCalls = append(decodedCalls, decodedCall)
+	}
+	if len(decodedCalls) == 0 {
+		return nil
+	}
+	return repository.CreateDecodedCalls(decodedCalls)
+}
+
+func parseWatchedAbi(watchedAbis map[string]string, contractHash string) *abi.ABI {
+	abiString, ok := watchedAbis[contractHash]
+	if !ok {
+		return nil
+	}
+	contractAbi, err := geth.ParseAbi(abiString)
+	if err != nil {
+		return nil
+	}
+	return &contractAbi
+}
+
+func saveBlock(repository repositories.Repository, block core.Block) {
+	repository.CreateOrUpdateBlock(block)
+	DecodeContractCalls(repository, block.Transactions)
+}
+
+// saveBlocks returns an error if the blocks are not saved. Calls that cannot
+// be decoded are left out, as the blocks are saved by then.
+func saveBlocks(repository repositories.Repository, blocks []core.Block) error {
+	err := repository.CreateOrUpdateBlocks(blocks)
+	if err != nil {
+		return err
+	}
+	var transactions []core.Transaction
+	for _, block := range blocks {
+		transactions = append(transactions, block.Transactions...)
+	}
+	DecodeContractCalls(repository, transactions)
+	return nil
+}
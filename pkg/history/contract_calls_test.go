@@ -0,0 +1,65 @@
+package history_test
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/testing"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decoding contract calls", func() {
+
+	var repository *repositories.InMemory
+	var contract core.Contract
+	var transferTransaction core.Transaction
+
+	BeforeEach(func() {
+		repository = repositories.NewInMemory()
+		contract = testing.SampleContract()
+		contractAbi, _ := geth.ParseAbi(contract.Abi)
+		input, _ := contractAbi.Pack("transfer", common.HexToAddress("0xfbb1b73c4f0bda4f67dca266ce6ef42f520fbb98"), big.NewInt(900))
+		transferTransaction = core.Transaction{Hash: "x456", To: contract.Hash, Data: input}
+	})
+
+	It("decodes the transactions sent to a watched contract", func() {
+		repository.CreateContract(contract)
+		block := core.Block{Number: 1, Transactions: []core.Transaction{transferTransaction}}
+		blockchain := fakes.NewBlockchainWithBlocks([]core.Block{block})
+
+		history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		decodedCalls := repository.FindDecodedCalls(contract.Hash, "transfer")
+		Expect(len(decodedCalls)).To(Equal(1))
+		Expect(decodedCalls[0].TxHash).To(Equal("x456"))
+		Expect(decodedCalls[0].Arguments[1].Value).To(Equal("900"))
+	})
+
+	It("does not decode transactions sent to contracts that are not watched", func() {
+		block := core.Block{Number: 1, Transactions: []core.Transaction{transferTransaction}}
+		blockchain := fakes.NewBlockchainWithBlocks([]core.Block{block})
+
+		history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		Expect(repository.FindDecodedCalls(contract.Hash, "transfer")).To(BeNil())
+	})
+
+	It("decodes the transactions of populated blocks", func() {
+		repository.CreateContract(contract)
+		repository.CreateOrUpdateBlock(core.Block{Number: 2})
+		blockchain := fakes.NewBlockchainWithBlocks([]core.Block{
+			{Number: 1, Transactions: []core.Transaction{transferTransaction}},
+		})
+
+		history.PopulateMissingBlocks(context.Background(), blockchain, repository, 1)
+
+		Expect(len(repository.FindDecodedCalls(contract.Hash, "transfer"))).To(Equal(1))
+	})
+})
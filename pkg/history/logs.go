@@ -0,0 +1,118 @@
+package history
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+)
+
+// Number of blocks scanned for logs with each request to the node.
+const blocksPerLogRange = 1000
+
+// LogBackfiller scans a contract's logs in ranges of blocks, keeping a
+// checkpoint of the last range completed for the contract and topics so that
+// it resumes where the previous run stopped. Ranges that fail are recorded
+// and retried on the next run, which makes backfilling the same contract
+// again safe.
+type LogBackfiller struct {
+	blockchain core.Blockchain
+	repository repositories.Repository
+	saveLogs   func(logs []core.Log) error
+	// Topics, if set, limits the logs scanned to those with matching topics,
+	// as in core.LogFilter.
+	Topics [][]string
+	// Progress is called after each range is scanned, with the error if the
+	// range failed.
+	Progress func(logRange core.LogRange, err error)
+}
+
+// NewLogBackfiller creates a LogBackfiller that saves the logs of each range
+// with saveLogs, or with the repository if saveLogs is nil.
+func NewLogBackfiller(blockchain core.Blockchain, repository repositories.Repository, saveLogs func(logs []core.Log) error) LogBackfiller {
+	if saveLogs == nil {
+		saveLogs = repository.CreateLogs
+	}
+	return LogBackfiller{
+		blockchain: blockchain,
+		repository: repository,
+		saveLogs:   saveLogs,
+	}
+}
+
+// Backfill retries the ranges that failed before, then scans the blocks
+// from startingBlockNumber, or after the checkpoint if it is later, up to
+// endingBlockNumber. It returns when the context is done, after the range in
+// progress, or when progress cannot be saved. An empty contract hash scans
+// the logs of every contract matching the topics. Checkpoints are kept per
+// contract hash and topics, so scanning for other topics starts over from
+// startingBlockNumber.
+func (backfiller LogBackfiller) Backfill(ctx context.Context, contractHash string, startingBlockNumber int64, endingBlockNumber int64) error {
+	topicsKey := core.LogFilter{Topics: backfiller.Topics}.TopicsKey()
+	for _, logRange := range backfiller.repository.FindFailedLogRanges(contractHash, topicsKey) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if backfiller.scan(ctx, logRange) == nil {
+			err := backfiller.repository.DeleteFailedLogRange(logRange)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	checkpoint, err := backfiller.repository.FindLogCheckpoint(contractHash, topicsKey)
+	if err == nil && checkpoint >= startingBlockNumber {
+		startingBlockNumber = checkpoint + 1
+	}
+	for start := startingBlockNumber; start <= endingBlockNumber; start += blocksPerLogRange {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logRange := core.LogRange{
+			ContractHash:        contractHash,
+			TopicsKey:           topicsKey,
+			StartingBlockNumber: start,
+			EndingBlockNumber:   start + blocksPerLogRange - 1,
+		}
+		if logRange.EndingBlockNumber > endingBlockNumber {
+			logRange.EndingBlockNumber = endingBlockNumber
+		}
+		err := backfiller.scan(ctx, logRange)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			err = backfiller.repository.CreateFailedLogRange(logRange)
+			if err != nil {
+				return err
+			}
+		}
+		err = backfiller.repository.UpdateLogCheckpoint(contractHash, topicsKey, logRange.EndingBlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (backfiller LogBackfiller) scan(ctx context.Context, logRange core.LogRange) error {
+	filter := core.LogFilter{Topics: backfiller.Topics}
+	if logRange.ContractHash != "" {
+		filter.Addresses = []string{logRange.ContractHash}
+	}
+	logs, err := backfiller.blockchain.FilterLogs(
+		ctx,
+		filter,
+		big.NewInt(logRange.StartingBlockNumber),
+		big.NewInt(logRange.EndingBlockNumber),
+	)
+	if err == nil {
+		err = backfiller.saveLogs(logs)
+	}
+	if backfiller.Progress != nil {
+		backfiller.Progress(logRange, err)
+	}
+	return err
+}
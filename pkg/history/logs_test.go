@@ -0,0 +1,210 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backfilling logs", func() {
+	var blockchain *fakes.Blockchain
+	var repository *repositories.InMemory
+	var scanned []core.LogRange
+	var backfiller history.LogBackfiller
+
+	BeforeEach(func() {
+		blockchain = fakes.NewBlockchain()
+		blockchain.SetLogs("x123", []core.Log{
+			{BlockNumber: 5, Index: 0, Address: "x123"},
+			{BlockNumber: 1500, Index: 0, Address: "x123"},
+			{BlockNumber: 2400, Index: 0, Address: "x123"},
+		})
+		repository = repositories.NewInMemory()
+		scanned = nil
+		backfiller = history.NewLogBackfiller(blockchain, repository, nil)
+		backfiller.Progress = func(logRange core.LogRange, err error) {
+			scanned = append(scanned, logRange)
+		}
+	})
+
+	It("scans the blocks in ranges and saves the logs", func() {
+		err := backfiller.Backfill(context.Background(), "x123", 0, 2500)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scanned).To(Equal([]core.LogRange{
+			{ContractHash: "x123", StartingBlockNumber: 0, EndingBlockNumber: 999},
+			{ContractHash: "x123", StartingBlockNumber: 1000, EndingBlockNumber: 1999},
+			{ContractHash: "x123", StartingBlockNumber: 2000, EndingBlockNumber: 2500},
+		}))
+		Expect(len(repository.FindLogsInRange("x123", 0, 2500))).To(Equal(3))
+		checkpoint, err := repository.FindLogCheckpoint("x123", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint).To(Equal(int64(2500)))
+	})
+
+	It("resumes after the last range completed", func() {
+		repository.UpdateLogCheckpoint("x123", "", 1999)
+
+		backfiller.Backfill(context.Background(), "x123", 0, 2500)
+
+		Expect(scanned).To(Equal([]core.LogRange{
+			{ContractHash: "x123", StartingBlockNumber: 2000, EndingBlockNumber: 2500},
+		}))
+	})
+
+	It("starts at the starting block without a checkpoint", func() {
+		backfiller.Backfill(context.Background(), "x123", 1500, 2500)
+
+		Expect(scanned).To(Equal([]core.LogRange{
+			{ContractHash: "x123", StartingBlockNumber: 1500, EndingBlockNumber: 2499},
+			{ContractHash: "x123", StartingBlockNumber: 2500, EndingBlockNumber: 2500},
+		}))
+		Expect(repository.FindLogsInRange("x123", 0, 1499)).To(BeEmpty())
+	})
+
+	It("does nothing when the checkpoint is at the ending block", func() {
+		repository.UpdateLogCheckpoint("x123", "", 2500)
+
+		err := backfiller.Backfill(context.Background(), "x123", 0, 2500)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scanned).To(BeEmpty())
+	})
+
+	It("records the ranges that fail and moves on", func() {
+		blockchain.SetLogsError(1000, errors.New("query timeout exceeded"))
+
+		err := backfiller.Backfill(context.Background(), "x123", 0, 2500)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository.FindFailedLogRanges("x123", "")).To(Equal([]core.LogRange{
+			{ContractHash: "x123", StartingBlockNumber: 1000, EndingBlockNumber: 1999},
+		}))
+		Expect(repository.FindLogsInRange("x123", 1000, 1999)).To(BeEmpty())
+		checkpoint, _ := repository.FindLogCheckpoint("x123", "")
+		Expect(checkpoint).To(Equal(int64(2500)))
+	})
+
+	It("retries the failed ranges on the next run", func() {
+		blockchain.SetLogsError(1000, errors.New("query timeout exceeded"))
+		backfiller.Backfill(context.Background(), "x123", 0, 2500)
+		blockchain.ClearLogsError(1000)
+		scanned = nil
+
+		err := backfiller.Backfill(context.Background(), "x123", 0, 2500)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scanned).To(Equal([]core.LogRange{
+			{ContractHash: "x123", StartingBlockNumber: 1000, EndingBlockNumber: 1999},
+		}))
+		Expect(repository.FindFailedLogRanges("x123", "")).To(BeEmpty())
+		Expect(len(repository.FindLogsInRange("x123", 1000, 1999))).To(Equal(1))
+	})
+
+	It("records a range as failed when its logs cannot be saved", func() {
+		backfiller = history.NewLogBackfiller(blockchain, repository, func(logs []core.Log) error {
+			return errors.New("postgres: insert failed")
+		})
+
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		Expect(repository.FindFailedLogRanges("x123", "")).To(Equal([]core.LogRange{
+			{ContractHash: "x123", StartingBlockNumber: 0, EndingBlockNumber: 999},
+		}))
+	})
+
+	It("only saves the logs with matching topics", func() {
+		blockchain.SetLogs("x123", []core.Log{
+			{BlockNumber: 5, Index: 0, Address: "x123", Topics: map[int]string{0: "xtransfer", 1: "xalice"}},
+			{BlockNumber: 6, Index: 0, Address: "x123", Topics: map[int]string{0: "xtransfer", 1: "xbob"}},
+			{BlockNumber: 7, Index: 0, Address: "x123", Topics: map[int]string{0: "xapproval", 1: "xalice"}},
+		})
+		backfiller.Topics = [][]string{{"xtransfer"}, {"xalice"}}
+
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		logs := repository.FindLogsInRange("x123", 0, 999)
+		Expect(len(logs)).To(Equal(1))
+		Expect(logs[0].BlockNumber).To(Equal(int64(5)))
+	})
+
+	It("scans the blocks again for other topics", func() {
+		blockchain.SetLogs("x123", []core.Log{
+			{BlockNumber: 5, Index: 0, Address: "x123", Topics: map[int]string{0: "xtransfer"}},
+			{BlockNumber: 6, Index: 0, Address: "x123", Topics: map[int]string{0: "xapproval"}},
+		})
+		backfiller.Topics = [][]string{{"xtransfer"}}
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		backfiller.Topics = [][]string{{"xapproval"}}
+		err := backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(repository.FindLogs("x123", 5))).To(Equal(1))
+		Expect(len(repository.FindLogs("x123", 6))).To(Equal(1))
+	})
+
+	It("scans the blocks again without topics after scanning for some", func() {
+		blockchain.SetLogs("x123", []core.Log{
+			{BlockNumber: 5, Index: 0, Address: "x123", Topics: map[int]string{0: "xtransfer"}},
+			{BlockNumber: 6, Index: 0, Address: "x123", Topics: map[int]string{0: "xapproval"}},
+		})
+		backfiller.Topics = [][]string{{"xtransfer"}}
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		backfiller.Topics = nil
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		Expect(len(repository.FindLogsInRange("x123", 0, 999))).To(Equal(2))
+	})
+
+	It("retries a failed range only with the topics it was scanned for", func() {
+		blockchain.SetLogsError(0, errors.New("connection reset"))
+		backfiller.Topics = [][]string{{"xtransfer"}}
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+		blockchain.ClearLogsError(0)
+
+		backfiller.Topics = [][]string{{"xapproval"}}
+		backfiller.Backfill(context.Background(), "x123", 0, 999)
+
+		Expect(repository.FindFailedLogRanges("x123", "xtransfer")).To(Equal([]core.LogRange{
+			{ContractHash: "x123", TopicsKey: "xtransfer", StartingBlockNumber: 0, EndingBlockNumber: 999},
+		}))
+		Expect(repository.FindFailedLogRanges("x123", "xapproval")).To(BeEmpty())
+	})
+
+	It("scans the logs of every contract without a contract hash", func() {
+		blockchain.SetLogs("x456", []core.Log{
+			{BlockNumber: 8, Index: 1, Address: "x456", Topics: map[int]string{0: "xtransfer"}},
+		})
+		blockchain.SetLogs("x123", []core.Log{
+			{BlockNumber: 8, Index: 0, Address: "x123", Topics: map[int]string{0: "xtransfer"}},
+			{BlockNumber: 9, Index: 0, Address: "x123", Topics: map[int]string{0: "xapproval"}},
+		})
+		backfiller.Topics = [][]string{{"xtransfer"}}
+
+		backfiller.Backfill(context.Background(), "", 0, 999)
+
+		Expect(len(repository.FindLogs("x123", 8))).To(Equal(1))
+		Expect(len(repository.FindLogs("x456", 8))).To(Equal(1))
+		Expect(repository.FindLogs("x123", 9)).To(BeEmpty())
+	})
+
+	It("stops without moving the checkpoint when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := backfiller.Backfill(ctx, "x123", 0, 2500)
+
+		Expect(err).To(Equal(context.Canceled))
+		Expect(scanned).To(BeEmpty())
+		_, err = repository.FindLogCheckpoint("x123", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,8 @@
 package history
 
 import (
+	"context"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/repositories"
 )
@@ -15,27 +17,37 @@ func (window Window) Size() int {
 	return int(window.UpperBound - window.LowerBound)
 }
 
-func PopulateMissingBlocks(blockchain core.Blockchain, repository repositories.Repository, startingBlockNumber int64) int {
-	blockRange := repository.MissingBlockNumbers(startingBlockNumber, repository.MaxBlockNumber())
-	updateBlockRange(blockchain, repository, blockRange)
-	return len(blockRange)
+func PopulateMissingBlocks(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, startingBlockNumber int64) int {
+	return NewBackfiller(blockchain, repository, 1).PopulateMissingBlocks(ctx, startingBlockNumber)
 }
 
-func UpdateBlocksWindow(blockchain core.Blockchain, repository repositories.Repository, windowSize int) Window {
+func UpdateBlocksWindow(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, windowSize int) Window {
 	maxBlockNumber := repository.MaxBlockNumber()
 	upperBound := repository.MaxBlockNumber() - int64(2)
 	lowerBound := upperBound - int64(windowSize)
 	blockRange := MakeRange(lowerBound, upperBound)
-	updateBlockRange(blockchain, repository, blockRange)
+	updateBlockRange(ctx, blockchain, repository, blockRange)
 	return Window{int(lowerBound), int(upperBound), int(maxBlockNumber)}
 }
 
-func updateBlockRange(blockchain core.Blockchain, repository repositories.Repository, blockNumbers []int64) int {
-	for _, blockNumber := range blockNumbers {
-		block := blockchain.GetBlockByNumber(blockNumber)
-		repository.CreateOrUpdateBlock(block)
+// updateBlockRange saves the blocks, skipping those that cannot be retrieved,
+// and returns the number saved.
+func updateBlockRange(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, blockNumbers []int64) int {
+	blocks, err := blockchain.GetBlocksByNumber(ctx, blockNumbers)
+	if err != nil || len(blocks) != len(blockNumbers) {
+		blocks = nil
+		for _, blockNumber := range blockNumbers {
+			block, err := blockchain.GetBlockByNumber(ctx, blockNumber)
+			if err != nil {
+				continue
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	for _, block := range blocks {
+		saveBlock(repository, block)
 	}
-	return len(blockNumbers)
+	return len(blocks)
 }
 
 func MakeRange(min, max int64) []int64 {
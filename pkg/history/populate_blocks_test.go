@@ -1,6 +1,8 @@
 package history_test
 
 import (
+	"context"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/history"
@@ -17,7 +19,7 @@ var _ = Describe("Populating blocks", func() {
 		repository := repositories.NewInMemory()
 		repository.CreateOrUpdateBlock(core.Block{Number: 2})
 
-		history.PopulateMissingBlocks(blockchain, repository, 1)
+		history.PopulateMissingBlocks(context.Background(), blockchain, repository, 1)
 
 		block, err := repository.FindBlockByNumber(1)
 		Expect(err).ToNot(HaveOccurred())
@@ -42,7 +44,7 @@ var _ = Describe("Populating blocks", func() {
 		repository.CreateOrUpdateBlock(core.Block{Number: 11})
 		repository.CreateOrUpdateBlock(core.Block{Number: 12})
 
-		history.PopulateMissingBlocks(blockchain, repository, 5)
+		history.PopulateMissingBlocks(context.Background(), blockchain, repository, 5)
 
 		Expect(repository.BlockCount()).To(Equal(11))
 		_, err := repository.FindBlockByNumber(4)
@@ -66,9 +68,10 @@ var _ = Describe("Populating blocks", func() {
 			{Number: 5},
 		})
 		repository := repositories.NewInMemory()
-		repository.CreateOrUpdateBlock(blockchain.GetBlockByNumber(5))
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 5)
+		repository.CreateOrUpdateBlock(block)
 
-		history.UpdateBlocksWindow(blockchain, repository, 2)
+		history.UpdateBlocksWindow(context.Background(), blockchain, repository, 2)
 
 		Expect(repository.BlockCount()).To(Equal(3))
 		Expect(repository.HandleBlockCallCount).To(Equal(3))
@@ -90,7 +93,7 @@ var _ = Describe("Populating blocks", func() {
 		repository.CreateOrUpdateBlock(core.Block{Number: 3})
 		repository.CreateOrUpdateBlock(core.Block{Number: 6})
 
-		numberOfBlocksCreated := history.PopulateMissingBlocks(blockchain, repository, 3)
+		numberOfBlocksCreated := history.PopulateMissingBlocks(context.Background(), blockchain, repository, 3)
 
 		Expect(numberOfBlocksCreated).To(Equal(2))
 	})
@@ -106,7 +109,7 @@ var _ = Describe("Populating blocks", func() {
 			{Number: 2},
 			{Number: 3},
 		})
-		maxBlockNumber := blockchain.LastBlock()
+		maxBlockNumber, _ := blockchain.LastBlock(context.Background())
 
 		Expect(maxBlockNumber.Int64()).To(Equal(int64(3)))
 	})
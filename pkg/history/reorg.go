@@ -0,0 +1,68 @@
+package history
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+)
+
+// MaxReorgDepth is the number of orphaned blocks replaced before giving up on
+// finding the common ancestor.
+const MaxReorgDepth = 64
+
+var ErrReorgTooDeep = errors.New("reorganisation deeper than the maximum depth")
+
+type Reorg struct {
+	CommonAncestor int64
+	OrphanedBlocks []core.Block
+}
+
+func (reorg Reorg) Depth() int {
+	return len(reorg.OrphanedBlocks)
+}
+
+// CreateBlock saves the block after replacing its orphaned ancestors. If an
+// ancestor cannot be retrieved, or the common ancestor is not found within
+// MaxReorgDepth blocks, the block is not saved, so that it is not stored on
+// top of an orphaned chain, and the error is returned with the blocks
+// replaced so far. The block is then left for the backfill.
+func CreateBlock(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, block core.Block) (Reorg, error) {
+	reorg, err := ReplaceOrphanedAncestors(ctx, blockchain, repository, block)
+	if err != nil {
+		return reorg, err
+	}
+	saveBlock(repository, block)
+	return reorg, nil
+}
+
+// ReplaceOrphanedAncestors follows the parent hashes of block back through the
+// repository until it reaches a stored block that is still part of the chain,
+// replacing every stored block along the way with the one from the blockchain.
+// It returns ErrReorgTooDeep after replacing MaxReorgDepth blocks.
+func ReplaceOrphanedAncestors(ctx context.Context, blockchain core.Blockchain, repository repositories.Repository, block core.Block) (Reorg, error) {
+	reorg := Reorg{CommonAncestor: block.Number - 1}
+	child := block
+	for blockNumber := block.Number - 1; blockNumber >= 0; blockNumber-- {
+		storedBlock, err := repository.FindBlockByNumber(blockNumber)
+		if err != nil || storedBlock.Hash == child.ParentHash {
+			break
+		}
+		if reorg.Depth() == MaxReorgDepth {
+			return reorg, ErrReorgTooDeep
+		}
+		canonicalBlock, err := blockchain.GetBlockByNumber(ctx, blockNumber)
+		if err != nil {
+			return reorg, err
+		}
+		if canonicalBlock.Hash == storedBlock.Hash {
+			break
+		}
+		saveBlock(repository, canonicalBlock)
+		reorg.OrphanedBlocks = append(reorg.OrphanedBlocks, storedBlock)
+		reorg.CommonAncestor = blockNumber - 1
+		child = canonicalBlock
+	}
+	return reorg, nil
+}
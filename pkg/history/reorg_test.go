@@ -0,0 +1,127 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handling chain reorganisations", func() {
+
+	var blockchain *fakes.Blockchain
+	var repository *repositories.InMemory
+
+	BeforeEach(func() {
+		blockchain = fakes.NewBlockchainWithBlocks([]core.Block{
+			{Number: 1, Hash: "x1", ParentHash: "x0"},
+			{Number: 2, Hash: "x2", ParentHash: "x1"},
+			{Number: 3, Hash: "x3", ParentHash: "x2"},
+			{Number: 4, Hash: "x4", ParentHash: "x3"},
+			{Number: 5, Hash: "x5", ParentHash: "x4"},
+		})
+		repository = repositories.NewInMemory()
+		repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "x1", ParentHash: "x0"})
+		repository.CreateOrUpdateBlock(core.Block{Number: 2, Hash: "x2", ParentHash: "x1"})
+	})
+
+	It("saves the block when its parent is the stored block below it", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "x3", ParentHash: "x2"})
+		repository.CreateOrUpdateBlock(core.Block{Number: 4, Hash: "x4", ParentHash: "x3"})
+
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 5)
+
+		reorg, _ := history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		Expect(reorg.Depth()).To(Equal(0))
+		Expect(repository.BlockCount()).To(Equal(5))
+		Expect(repository.FindOrphanedBlocks(4)).To(BeEmpty())
+	})
+
+	It("replaces every orphaned block back to the common ancestor", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "y3", ParentHash: "x2"})
+		repository.CreateOrUpdateBlock(core.Block{Number: 4, Hash: "y4", ParentHash: "y3"})
+
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 5)
+
+		reorg, _ := history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		Expect(reorg.Depth()).To(Equal(2))
+		Expect(reorg.CommonAncestor).To(Equal(int64(2)))
+		Expect(reorg.OrphanedBlocks[0].Hash).To(Equal("y4"))
+		Expect(reorg.OrphanedBlocks[1].Hash).To(Equal("y3"))
+		blockThree, _ := repository.FindBlockByNumber(3)
+		Expect(blockThree.Hash).To(Equal("x3"))
+		blockFour, _ := repository.FindBlockByNumber(4)
+		Expect(blockFour.Hash).To(Equal("x4"))
+		blockFive, _ := repository.FindBlockByNumber(5)
+		Expect(blockFive.Hash).To(Equal("x5"))
+	})
+
+	It("records the blocks that were replaced", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "y3", ParentHash: "x2"})
+		repository.CreateOrUpdateBlock(core.Block{Number: 4, Hash: "y4", ParentHash: "y3"})
+
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 5)
+
+		history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		orphanedBlocks := repository.FindOrphanedBlocks(3)
+		Expect(len(orphanedBlocks)).To(Equal(1))
+		Expect(orphanedBlocks[0].Hash).To(Equal("y3"))
+	})
+
+	It("does not save the block and returns the error when an ancestor cannot be retrieved", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "y3", ParentHash: "x2"})
+		repository.CreateOrUpdateBlock(core.Block{Number: 4, Hash: "y4", ParentHash: "y3"})
+		blockchain.SetBlockError(3, errors.New("connection reset"))
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 5)
+
+		reorg, err := history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		Expect(err).To(MatchError("connection reset"))
+		Expect(reorg.Depth()).To(Equal(1))
+		_, err = repository.FindBlockByNumber(5)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("gives up on a reorganisation deeper than the maximum depth", func() {
+		head := int64(history.MaxReorgDepth + 3)
+		var canonicalBlocks []core.Block
+		for number := int64(1); number <= head; number++ {
+			canonicalBlocks = append(canonicalBlocks, core.Block{Number: number, Hash: fmt.Sprintf("x%d", number), ParentHash: fmt.Sprintf("x%d", number-1)})
+			if number > 1 && number < head {
+				repository.CreateOrUpdateBlock(core.Block{Number: number, Hash: fmt.Sprintf("y%d", number), ParentHash: fmt.Sprintf("y%d", number-1)})
+			}
+		}
+		blockchain = fakes.NewBlockchainWithBlocks(canonicalBlocks)
+		block, _ := blockchain.GetBlockByNumber(context.Background(), head)
+
+		reorg, err := history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		Expect(err).To(Equal(history.ErrReorgTooDeep))
+		Expect(reorg.Depth()).To(Equal(history.MaxReorgDepth))
+		_, err = repository.FindBlockByNumber(head)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("stops walking back at a gap in the stored blocks", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 4, Hash: "y4", ParentHash: "y3"})
+
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 5)
+
+		reorg, _ := history.CreateBlock(context.Background(), blockchain, repository, block)
+
+		Expect(reorg.Depth()).To(Equal(1))
+		Expect(reorg.CommonAncestor).To(Equal(int64(3)))
+		_, err := repository.FindBlockByNumber(3)
+		Expect(err).To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,100 @@
+package notifications
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	"github.com/lib/pq"
+)
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	bufferSize           = 100
+)
+
+// Listener LISTENs for the notifications sent by repositories.Postgres and
+// delivers them on its Blocks and Logs channels, which are closed after Close.
+// Consumers must drain both channels, since a full channel holds up the other.
+type Listener struct {
+	Blocks    chan repositories.BlockNotification
+	Logs      chan repositories.LogNotification
+	listener  *pq.Listener
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewListener(databaseConfig config.Database) (*Listener, error) {
+	pqListener := pq.NewListener(config.DbConnectionString(databaseConfig), minReconnectInterval, maxReconnectInterval, nil)
+	for _, channel := range []string{repositories.BlocksChannel, repositories.LogsChannel} {
+		err := pqListener.Listen(channel)
+		if err != nil {
+			pqListener.Close()
+			return nil, err
+		}
+	}
+	listener := &Listener{
+		Blocks:   make(chan repositories.BlockNotification, bufferSize),
+		Logs:     make(chan repositories.LogNotification, bufferSize),
+		listener: pqListener,
+		done:     make(chan struct{}),
+	}
+	go listener.dispatch()
+	return listener, nil
+}
+
+// Close stops the delivery of notifications, including one waiting for a
+// consumer that stopped reading.
+func (listener *Listener) Close() error {
+	var err error
+	listener.closeOnce.Do(func() {
+		close(listener.done)
+		err = listener.listener.Close()
+	})
+	return err
+}
+
+func (listener *Listener) dispatch() {
+	defer close(listener.Blocks)
+	defer close(listener.Logs)
+	for {
+		var notification *pq.Notification
+		var ok bool
+		select {
+		case notification, ok = <-listener.listener.Notify:
+			if !ok {
+				return
+			}
+		case <-listener.done:
+			return
+		}
+		// pq sends nil after reconnecting, notifications sent while
+		// disconnected are lost
+		if notification == nil {
+			continue
+		}
+		switch notification.Channel {
+		case repositories.BlocksChannel:
+			var block repositories.BlockNotification
+			if json.Unmarshal([]byte(notification.Extra), &block) == nil {
+				select {
+				case listener.Blocks <- block:
+				case <-listener.done:
+					return
+				}
+			}
+		case repositories.LogsChannel:
+			var log repositories.LogNotification
+			if json.Unmarshal([]byte(notification.Extra), &log) == nil {
+				select {
+				case listener.Logs <- log:
+				case <-listener.done:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package notifications_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/notifications"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories/testing"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Listening for notifications", func() {
+	var repository repositories.Postgres
+	var listener *notifications.Listener
+	var nodeId int64
+
+	BeforeEach(func() {
+		cfg, _ := config.NewConfig("private")
+		node := core.Node{GenesisBlock: "GENESIS", NetworkId: 1}
+		repository, _ = repositories.NewPostgres(cfg.Database, node)
+		testing.ClearData(repository)
+		repository.Db.Get(&nodeId, `SELECT id FROM nodes WHERE genesis_block = $1 AND network_id = $2`, node.GenesisBlock, node.NetworkId)
+		var err error
+		listener, err = notifications.NewListener(cfg.Database)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	It("delivers a notification when a block is inserted", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 123, Hash: "x123"})
+
+		Eventually(listener.Blocks, time.Second).Should(Receive(Equal(repositories.BlockNotification{
+			Action: repositories.BlockInserted,
+			Number: 123,
+			Hash:   "x123",
+			NodeId: nodeId,
+		})))
+	})
+
+	It("delivers a notification when a block is removed", func() {
+		repository.CreateOrUpdateBlock(core.Block{Number: 123, Hash: "x123"})
+		repository.CreateOrUpdateBlock(core.Block{Number: 123, Hash: "x456"})
+
+		var received []repositories.BlockNotification
+		for i := 0; i < 3; i++ {
+			var notification repositories.BlockNotification
+			Eventually(listener.Blocks, time.Second).Should(Receive(&notification))
+			received = append(received, notification)
+		}
+		Expect(received).To(Equal([]repositories.BlockNotification{
+			{Action: repositories.BlockInserted, Number: 123, Hash: "x123", NodeId: nodeId},
+			{Action: repositories.BlockRemoved, Number: 123, Hash: "x123", NodeId: nodeId},
+			{Action: repositories.BlockInserted, Number: 123, Hash: "x456", NodeId: nodeId},
+		}))
+	})
+
+	It("delivers one notification per contract and block when logs are saved", func() {
+		repository.CreateLogs([]core.Log{
+			{BlockNumber: 1, Index: 0, Address: "x123", Topics: map[int]string{}},
+			{BlockNumber: 1, Index: 1, Address: "x123", Topics: map[int]string{}},
+			{BlockNumber: 1, Index: 2, Address: "x456", Topics: map[int]string{}},
+		})
+
+		Eventually(listener.Logs, time.Second).Should(Receive(Equal(repositories.LogNotification{Address: "x123", BlockNumber: 1, NodeId: nodeId})))
+		Eventually(listener.Logs, time.Second).Should(Receive(Equal(repositories.LogNotification{Address: "x456", BlockNumber: 1, NodeId: nodeId})))
+		Consistently(listener.Logs, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("delivers a notification for the receipt logs of an inserted block", func() {
+		repository.CreateOrUpdateBlock(core.Block{
+			Number: 123,
+			Hash:   "x123",
+			Transactions: []core.Transaction{{
+				Hash:    "x456",
+				Receipt: core.Receipt{TxHash: "x456", Logs: []core.Log{{BlockNumber: 123, Index: 0, Address: "x789", TxHash: "x456", Topics: map[int]string{}}}},
+			}},
+		})
+
+		Eventually(listener.Logs, time.Second).Should(Receive(Equal(repositories.LogNotification{Address: "x789", BlockNumber: 123, NodeId: nodeId})))
+	})
+
+	It("does not deliver a notification for a block that is not committed", func() {
+		//badNonce violates db Nonce field length
+		badNonce := strings.Repeat("1", 100)
+
+		repository.CreateOrUpdateBlock(core.Block{Number: 123, Nonce: badNonce})
+
+		Consistently(listener.Blocks, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("closes its channels when closed", func() {
+		listener.Close()
+
+		Eventually(listener.Blocks).Should(BeClosed())
+		Eventually(listener.Logs).Should(BeClosed())
+	})
+
+	It("closes its channels when closed while a consumer is not reading", func() {
+		var logs []core.Log
+		for i := 0; i <= cap(listener.Logs); i++ {
+			logs = append(logs, core.Log{BlockNumber: 1, Index: int64(i), Address: fmt.Sprintf("x%d", i), Topics: map[int]string{}})
+		}
+		repository.CreateLogs(logs)
+		Eventually(func() int { return len(listener.Logs) }, time.Second).Should(Equal(cap(listener.Logs)))
+
+		listener.Close()
+
+		drained := make(chan bool)
+		go func() {
+			for range listener.Logs {
+			}
+			close(drained)
+		}()
+		Eventually(drained, time.Second).Should(BeClosed())
+		Eventually(listener.Blocks).Should(BeClosed())
+	})
+})
@@ -1,18 +1,29 @@
 package observers
 
 import (
+	"context"
+	"log"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"github.com/vulcanize/vulcanizedb/pkg/repositories"
 )
 
 type BlockchainDbObserver struct {
+	blockchain core.Blockchain
 	repository repositories.Repository
 }
 
-func NewBlockchainDbObserver(repository repositories.Repository) BlockchainDbObserver {
-	return BlockchainDbObserver{repository: repository}
+func NewBlockchainDbObserver(blockchain core.Blockchain, repository repositories.Repository) BlockchainDbObserver {
+	return BlockchainDbObserver{blockchain: blockchain, repository: repository}
 }
 
-func (observer BlockchainDbObserver) NotifyBlockAdded(block core.Block) {
-	observer.repository.CreateOrUpdateBlock(block)
+func (observer BlockchainDbObserver) NotifyBlockAdded(ctx context.Context, block core.Block) {
+	reorg, err := history.CreateBlock(ctx, observer.blockchain, observer.repository, block)
+	if err != nil {
+		log.Printf("Error replacing orphaned blocks below block %d, leaving it for the backfill\n%v", block.Number, err)
+	}
+	if reorg.Depth() > 0 {
+		log.Printf("Replaced %d orphaned blocks above block %d\n", reorg.Depth(), reorg.CommonAncestor)
+	}
 }
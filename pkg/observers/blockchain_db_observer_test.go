@@ -1,7 +1,10 @@
 package observers_test
 
 import (
+	"context"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/observers"
 	"github.com/vulcanize/vulcanizedb/pkg/repositories"
 	. "github.com/onsi/ginkgo"
@@ -10,14 +13,16 @@ import (
 
 var _ = Describe("Saving blocks to the database", func() {
 
+	var blockchain *fakes.Blockchain
 	var repository *repositories.InMemory
 
 	BeforeEach(func() {
+		blockchain = fakes.NewBlockchain()
 		repository = repositories.NewInMemory()
 	})
 
 	It("implements the observer interface", func() {
-		var observer core.BlockchainObserver = observers.NewBlockchainDbObserver(repository)
+		var observer core.BlockchainObserver = observers.NewBlockchainDbObserver(blockchain, repository)
 		Expect(observer).NotTo(BeNil())
 	})
 
@@ -27,12 +32,29 @@ var _ = Describe("Saving blocks to the database", func() {
 			Transactions: []core.Transaction{{}},
 		}
 
-		observer := observers.NewBlockchainDbObserver(repository)
-		observer.NotifyBlockAdded(block)
+		observer := observers.NewBlockchainDbObserver(blockchain, repository)
+		observer.NotifyBlockAdded(context.Background(), block)
 
 		savedBlock, err := repository.FindBlockByNumber(123)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(len(savedBlock.Transactions)).To(Equal(1))
 	})
 
+	It("replaces stored blocks that are no longer part of the chain", func() {
+		blockchain = fakes.NewBlockchainWithBlocks([]core.Block{
+			{Number: 122, Hash: "x122"},
+			{Number: 123, Hash: "x123", ParentHash: "x122"},
+		})
+		repository.CreateOrUpdateBlock(core.Block{Number: 122, Hash: "y122"})
+
+		observer := observers.NewBlockchainDbObserver(blockchain, repository)
+		block, _ := blockchain.GetBlockByNumber(context.Background(), 123)
+		observer.NotifyBlockAdded(context.Background(), block)
+
+		savedBlock, err := repository.FindBlockByNumber(122)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(savedBlock.Hash).To(Equal("x122"))
+		Expect(repository.FindOrphanedBlocks(122)[0].Hash).To(Equal("y122"))
+	})
+
 })
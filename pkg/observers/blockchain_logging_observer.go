@@ -1,6 +1,7 @@
 package observers
 
 import (
+	"context"
 	"os"
 	"text/template"
 
@@ -27,6 +28,6 @@ var tmp = template.Must(template.New("window").Funcs(funcMap).Parse(blockAddedTe
 
 type BlockchainLoggingObserver struct{}
 
-func (blockchainObserver BlockchainLoggingObserver) NotifyBlockAdded(block core.Block) {
+func (blockchainObserver BlockchainLoggingObserver) NotifyBlockAdded(ctx context.Context, block core.Block) {
 	tmp.Execute(os.Stdout, block)
 }
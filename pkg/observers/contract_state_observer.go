@@ -0,0 +1,35 @@
+package observers
+
+import (
+	"context"
+	"log"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_state"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+)
+
+type ContractStateObserver struct {
+	blockchain core.Blockchain
+	repository repositories.Repository
+	interval   int64
+}
+
+// NewContractStateObserver records the state of the watched contracts at
+// every block whose number is a multiple of interval.
+func NewContractStateObserver(blockchain core.Blockchain, repository repositories.Repository, interval int64) ContractStateObserver {
+	if interval < 1 {
+		interval = 1
+	}
+	return ContractStateObserver{blockchain: blockchain, repository: repository, interval: interval}
+}
+
+func (observer ContractStateObserver) NotifyBlockAdded(ctx context.Context, block core.Block) {
+	if block.Number%observer.interval != 0 {
+		return
+	}
+	err := contract_state.RecordWatchedContractsState(ctx, observer.blockchain, observer.repository, block.Number)
+	if err != nil {
+		log.Printf("Error recording contract state at block %d\n%v", block.Number, err)
+	}
+}
@@ -0,0 +1,54 @@
+package observers_test
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/observers"
+	"github.com/vulcanize/vulcanizedb/pkg/repositories"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recording contract state as blocks are added", func() {
+
+	var blockchain *fakes.Blockchain
+	var repository *repositories.InMemory
+
+	BeforeEach(func() {
+		blockchain = fakes.NewBlockchain()
+		repository = repositories.NewInMemory()
+		repository.CreateContract(core.Contract{Hash: "x123"})
+		blockchain.SetContractStateAttribute("x123", nil, "totalSupply", "")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(10), "totalSupply", "1000")
+		blockchain.SetContractStateAttribute("x123", big.NewInt(11), "totalSupply", "1100")
+	})
+
+	It("implements the observer interface", func() {
+		var observer core.BlockchainObserver = observers.NewContractStateObserver(blockchain, repository, 1)
+		Expect(observer).NotTo(BeNil())
+	})
+
+	It("records the state of the watched contracts at the new block", func() {
+		observer := observers.NewContractStateObserver(blockchain, repository, 1)
+
+		observer.NotifyBlockAdded(context.Background(), core.Block{Number: 10})
+
+		states := repository.FindContractState("x123", "totalSupply")
+		Expect(len(states)).To(Equal(1))
+		Expect(states[0].Value).To(Equal("1000"))
+	})
+
+	It("only records the state at every interval blocks", func() {
+		observer := observers.NewContractStateObserver(blockchain, repository, 10)
+
+		observer.NotifyBlockAdded(context.Background(), core.Block{Number: 10})
+		observer.NotifyBlockAdded(context.Background(), core.Block{Number: 11})
+
+		states := repository.FindContractState("x123", "totalSupply")
+		Expect(len(states)).To(Equal(1))
+		Expect(states[0].BlockNumber).To(Equal(int64(10)))
+	})
+})
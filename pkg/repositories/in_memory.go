@@ -2,17 +2,30 @@ package repositories
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 )
 
 type InMemory struct {
 	blocks               map[int64]core.Block
+	orphanedBlocks       map[int64][]core.Block
 	contracts            map[string]core.Contract
 	logs                 map[string][]core.Log
+	decodedEvents        map[string]core.DecodedEvent
+	decodedCalls         map[string]core.DecodedCall
+	contractState        map[string]core.ContractState
+	logCheckpoints       map[logCheckpointKey]int64
+	failedLogRanges      map[core.LogRange]bool
 	HandleBlockCallCount int
 }
 
+type logCheckpointKey struct {
+	contractHash string
+	topicsKey    string
+}
+
 func (repository *InMemory) SetBlocksStatus(chainHead int64) {
 	for key, block := range repository.blocks {
 		if key < (chainHead - blocksFromHeadBeforeFinal) {
@@ -25,7 +38,7 @@ func (repository *InMemory) SetBlocksStatus(chainHead int64) {
 
 func (repository *InMemory) CreateLogs(logs []core.Log) error {
 	for _, log := range logs {
-		key := fmt.Sprintf("%s%s", log.BlockNumber, log.Index)
+		key := fmt.Sprintf("%d-%d", log.BlockNumber, log.Index)
 		var logs []core.Log
 		repository.logs[key] = append(logs, log)
 	}
@@ -44,7 +57,76 @@ func (repository *InMemory) FindLogs(address string, blockNumber int64) []core.L
 	return matchingLogs
 }
 
+func (repository *InMemory) FindLogsInRange(address string, startingBlockNumber int64, endingBlockNumber int64) []core.Log {
+	var matchingLogs []core.Log
+	for _, logs := range repository.logs {
+		for _, log := range logs {
+			if log.Address == address && log.BlockNumber >= startingBlockNumber && log.BlockNumber <= endingBlockNumber {
+				matchingLogs = append(matchingLogs, log)
+			}
+		}
+	}
+	sort.Slice(matchingLogs, func(i, j int) bool {
+		if matchingLogs[i].BlockNumber != matchingLogs[j].BlockNumber {
+			return matchingLogs[i].BlockNumber < matchingLogs[j].BlockNumber
+		}
+		return matchingLogs[i].Index < matchingLogs[j].Index
+	})
+	return matchingLogs
+}
+
+func (repository *InMemory) FindLogsPage(address string, startingBlockNumber int64, endingBlockNumber int64, after core.LogPosition, limit int) []core.Log {
+	var page []core.Log
+	for _, log := range repository.FindLogsInRange(address, startingBlockNumber, endingBlockNumber) {
+		if len(page) == limit {
+			break
+		}
+		if log.BlockNumber > after.BlockNumber || (log.BlockNumber == after.BlockNumber && log.Index > after.Index) {
+			page = append(page, log)
+		}
+	}
+	return page
+}
+
+func (repository *InMemory) CreateDecodedEvents(events []core.DecodedEvent) error {
+	for _, event := range events {
+		key := fmt.Sprintf("%d-%d", event.BlockNumber, event.LogIndex)
+		if _, ok := repository.logs[key]; !ok {
+			return ErrLogDoesNotExist(event.BlockNumber, event.LogIndex)
+		}
+		repository.decodedEvents[key] = event
+	}
+	return nil
+}
+
+func (repository *InMemory) FindDecodedEvents(address string, eventName string) []core.DecodedEvent {
+	var matchingEvents []core.DecodedEvent
+	for _, event := range repository.decodedEvents {
+		if event.Address == address && event.Name == eventName {
+			matchingEvents = append(matchingEvents, event)
+		}
+	}
+	sort.Slice(matchingEvents, func(i, j int) bool {
+		if matchingEvents[i].BlockNumber != matchingEvents[j].BlockNumber {
+			return matchingEvents[i].BlockNumber < matchingEvents[j].BlockNumber
+		}
+		return matchingEvents[i].LogIndex < matchingEvents[j].LogIndex
+	})
+	return matchingEvents
+}
+
+func (repository *InMemory) FindDecodedEvent(blockNumber int64, logIndex int64) (core.DecodedEvent, error) {
+	event, ok := repository.decodedEvents[fmt.Sprintf("%d-%d", blockNumber, logIndex)]
+	if !ok {
+		return core.DecodedEvent{}, ErrDecodedEventDoesNotExist(blockNumber, logIndex)
+	}
+	return event, nil
+}
+
 func (repository *InMemory) CreateContract(contract core.Contract) error {
+	if existing, ok := repository.contracts[contract.Hash]; ok && contract.DeploymentBlock == 0 {
+		contract.DeploymentBlock = existing.DeploymentBlock
+	}
 	repository.contracts[contract.Hash] = contract
 	return nil
 }
@@ -61,7 +143,7 @@ func (repository *InMemory) FindContract(contractHash string) (core.Contract, er
 	}
 	for _, block := range repository.blocks {
 		for _, transaction := range block.Transactions {
-			if transaction.To == contractHash {
+			if strings.EqualFold(transaction.To, contractHash) {
 				contract.Transactions = append(contract.Transactions, transaction)
 			}
 		}
@@ -69,6 +151,216 @@ func (repository *InMemory) FindContract(contractHash string) (core.Contract, er
 	return contract, nil
 }
 
+func (repository *InMemory) FindWatchedContracts() []core.Contract {
+	var contracts []core.Contract
+	for _, contract := range repository.contracts {
+		contracts = append(contracts, contract)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Hash < contracts[j].Hash
+	})
+	return contracts
+}
+
+func (repository *InMemory) CreateContractState(states []core.ContractState) error {
+	for _, state := range states {
+		key := fmt.Sprintf("%s-%d-%s", state.ContractHash, state.BlockNumber, state.AttributeName)
+		repository.contractState[key] = state
+	}
+	return nil
+}
+
+func (repository *InMemory) FindContractState(contractHash string, attributeName string) []core.ContractState {
+	var states []core.ContractState
+	for _, state := range repository.contractState {
+		if state.ContractHash == contractHash && state.AttributeName == attributeName {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].BlockNumber < states[j].BlockNumber
+	})
+	return states
+}
+
+func (repository *InMemory) FindLogCheckpoint(contractHash string, topicsKey string) (int64, error) {
+	blockNumber, ok := repository.logCheckpoints[logCheckpointKey{contractHash, topicsKey}]
+	if !ok {
+		return 0, ErrLogCheckpointDoesNotExist(contractHash)
+	}
+	return blockNumber, nil
+}
+
+func (repository *InMemory) UpdateLogCheckpoint(contractHash string, topicsKey string, blockNumber int64) error {
+	key := logCheckpointKey{contractHash, topicsKey}
+	if existing, ok := repository.logCheckpoints[key]; !ok || blockNumber > existing {
+		repository.logCheckpoints[key] = blockNumber
+	}
+	return nil
+}
+
+func (repository *InMemory) CreateFailedLogRange(logRange core.LogRange) error {
+	repository.failedLogRanges[logRange] = true
+	return nil
+}
+
+func (repository *InMemory) FindFailedLogRanges(contractHash string, topicsKey string) []core.LogRange {
+	var logRanges []core.LogRange
+	for logRange := range repository.failedLogRanges {
+		if logRange.ContractHash == contractHash && logRange.TopicsKey == topicsKey {
+			logRanges = append(logRanges, logRange)
+		}
+	}
+	sort.Slice(logRanges, func(i, j int) bool {
+		return logRanges[i].StartingBlockNumber < logRanges[j].StartingBlockNumber
+	})
+	return logRanges
+}
+
+func (repository *InMemory) DeleteFailedLogRange(logRange core.LogRange) error {
+	delete(repository.failedLogRanges, logRange)
+	return nil
+}
+
+func (repository *InMemory) CreateDecodedCalls(calls []core.DecodedCall) error {
+	for _, call := range calls {
+		if !repository.transactionExists(call.TxHash) {
+			return ErrTransactionDoesNotExist(call.TxHash)
+		}
+		repository.decodedCalls[call.TxHash] = call
+	}
+	return nil
+}
+
+func (repository *InMemory) FindDecodedCalls(contractHash string, methodName string) []core.DecodedCall {
+	var matchingCalls []core.DecodedCall
+	for _, call := range repository.decodedCalls {
+		if strings.EqualFold(call.To, contractHash) && call.Method == methodName {
+			matchingCalls = append(matchingCalls, call)
+		}
+	}
+	sort.Slice(matchingCalls, func(i, j int) bool {
+		return matchingCalls[i].TxHash < matchingCalls[j].TxHash
+	})
+	return matchingCalls
+}
+
+func (repository *InMemory) FindTransactions(address string, limit int, offset int) []core.Transaction {
+	var blockNumbers []int64
+	for blockNumber := range repository.blocks {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] > blockNumbers[j]
+	})
+	var matchingTransactions []core.Transaction
+	for _, blockNumber := range blockNumbers {
+		var blockTransactions []core.Transaction
+		for _, transaction := range repository.blocks[blockNumber].Transactions {
+			if strings.EqualFold(transaction.To, address) || strings.EqualFold(transaction.From, address) {
+				blockTransactions = append(blockTransactions, transaction)
+			}
+		}
+		sort.Slice(blockTransactions, func(i, j int) bool {
+			return blockTransactions[i].Hash < blockTransactions[j].Hash
+		})
+		matchingTransactions = append(matchingTransactions, blockTransactions...)
+	}
+	if offset >= len(matchingTransactions) {
+		return nil
+	}
+	matchingTransactions = matchingTransactions[offset:]
+	if limit < len(matchingTransactions) {
+		matchingTransactions = matchingTransactions[:limit]
+	}
+	return matchingTransactions
+}
+
+func (repository *InMemory) TransactionCount(address string) int {
+	var count int
+	for _, block := range repository.blocks {
+		for _, transaction := range block.Transactions {
+			if strings.EqualFold(transaction.To, address) || strings.EqualFold(transaction.From, address) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (repository *InMemory) transactionExists(txHash string) bool {
+	for _, block := range repository.blocks {
+		for _, transaction := range block.Transactions {
+			if transaction.Hash == txHash {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (repository *InMemory) FindDeploymentBlockNumber(contractHash string) (int64, error) {
+	for _, block := range repository.blocks {
+		for _, transaction := range block.Transactions {
+			if transaction.Receipt.ContractAddress != "" && strings.EqualFold(transaction.Receipt.ContractAddress, contractHash) {
+				return block.Number, nil
+			}
+		}
+	}
+	return 0, ErrDeploymentReceiptDoesNotExist(contractHash)
+}
+
+func (repository *InMemory) FindContractCreation(contractAddress string) (core.ContractCreation, error) {
+	for _, creation := range repository.contractCreations() {
+		if strings.EqualFold(creation.ContractAddress, contractAddress) {
+			return creation, nil
+		}
+	}
+	return core.ContractCreation{}, ErrContractCreationDoesNotExist(contractAddress)
+}
+
+func (repository *InMemory) FindContractCreationsByCreator(creatorAddress string) []core.ContractCreation {
+	var matchingCreations []core.ContractCreation
+	for _, creation := range repository.contractCreations() {
+		if strings.EqualFold(creation.CreatorAddress, creatorAddress) {
+			matchingCreations = append(matchingCreations, creation)
+		}
+	}
+	return matchingCreations
+}
+
+// contractCreations returns the creations in the saved blocks, ordered by
+// block number.
+func (repository *InMemory) contractCreations() []core.ContractCreation {
+	var blockNumbers []int64
+	for blockNumber := range repository.blocks {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	var creations []core.ContractCreation
+	for _, blockNumber := range blockNumbers {
+		for _, transaction := range repository.blocks[blockNumber].Transactions {
+			if transaction.CreatesContract() {
+				creations = append(creations, core.NewContractCreation(blockNumber, transaction))
+			}
+		}
+	}
+	return creations
+}
+
+func (repository *InMemory) FindReceipt(txHash string) (core.Receipt, error) {
+	for _, block := range repository.blocks {
+		for _, transaction := range block.Transactions {
+			if transaction.Receipt.TxHash == txHash {
+				return transaction.Receipt, nil
+			}
+		}
+	}
+	return core.Receipt{}, ErrReceiptDoesNotExist(txHash)
+}
+
 func (repository *InMemory) MissingBlockNumbers(startingBlockNumber int64, endingBlockNumber int64) []int64 {
 	missingNumbers := []int64{}
 	for blockNumber := int64(startingBlockNumber); blockNumber <= endingBlockNumber; blockNumber++ {
@@ -83,17 +375,68 @@ func NewInMemory() *InMemory {
 	return &InMemory{
 		HandleBlockCallCount: 0,
 		blocks:               make(map[int64]core.Block),
+		orphanedBlocks:       make(map[int64][]core.Block),
 		contracts:            make(map[string]core.Contract),
 		logs:                 make(map[string][]core.Log),
+		decodedEvents:        make(map[string]core.DecodedEvent),
+		decodedCalls:         make(map[string]core.DecodedCall),
+		contractState:        make(map[string]core.ContractState),
+		logCheckpoints:       make(map[logCheckpointKey]int64),
+		failedLogRanges:      make(map[core.LogRange]bool),
 	}
 }
 
 func (repository *InMemory) CreateOrUpdateBlock(block core.Block) error {
 	repository.HandleBlockCallCount++
+	if existingBlock, ok := repository.blocks[block.Number]; ok && existingBlock.Hash != block.Hash {
+		repository.orphanedBlocks[block.Number] = append(repository.orphanedBlocks[block.Number], existingBlock)
+		repository.removeLogs(block.Number)
+		repository.removeDecodedCalls(existingBlock)
+		repository.removeContractState(block.Number)
+	}
 	repository.blocks[block.Number] = block
+	for _, transaction := range block.Transactions {
+		repository.CreateLogs(transaction.Receipt.Logs)
+	}
 	return nil
 }
 
+func (repository *InMemory) CreateOrUpdateBlocks(blocks []core.Block) error {
+	for _, block := range blocks {
+		repository.CreateOrUpdateBlock(block)
+	}
+	return nil
+}
+
+func (repository *InMemory) removeLogs(blockNumber int64) {
+	for key, logs := range repository.logs {
+		for _, log := range logs {
+			if log.BlockNumber == blockNumber {
+				delete(repository.logs, key)
+				delete(repository.decodedEvents, key)
+			}
+		}
+	}
+}
+
+func (repository *InMemory) removeDecodedCalls(block core.Block) {
+	for _, transaction := range block.Transactions {
+		delete(repository.decodedCalls, transaction.Hash)
+	}
+}
+
+func (repository *InMemory) removeContractState(blockNumber int64) {
+	for key, state := range repository.contractState {
+		if state.BlockNumber == blockNumber {
+			delete(repository.contractState, key)
+		}
+	}
+}
+
+func (repository *InMemory) FindOrphanedBlocks(blockNumber int64) []core.Block {
+	return repository.orphanedBlocks[blockNumber]
+}
+
 func (repository *InMemory) BlockCount() int {
 	return len(repository.blocks)
 }
@@ -105,6 +448,15 @@ func (repository *InMemory) FindBlockByNumber(blockNumber int64) (core.Block, er
 	return core.Block{}, ErrBlockDoesNotExist(blockNumber)
 }
 
+func (repository *InMemory) FindBlockByHash(blockHash string) (core.Block, error) {
+	for _, block := range repository.blocks {
+		if block.Hash == blockHash {
+			return block, nil
+		}
+	}
+	return core.Block{}, ErrBlockHashDoesNotExist(blockHash)
+}
+
 func (repository *InMemory) MaxBlockNumber() int64 {
 	highestBlockNumber := int64(-1)
 	for key := range repository.blocks {
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+// Postgres sends a NOTIFY with a JSON payload on these channels when the
+// transaction that inserts or removes a block, or saves logs, commits.
+const (
+	BlocksChannel = "vulcanizedb_blocks"
+	LogsChannel   = "vulcanizedb_logs"
+)
+
+const (
+	BlockInserted = "insert"
+	BlockRemoved  = "remove"
+)
+
+type BlockNotification struct {
+	Action string `json:"action"`
+	Number int64  `json:"number"`
+	Hash   string `json:"hash"`
+	NodeId int64  `json:"node_id"`
+}
+
+type LogNotification struct {
+	Address     string `json:"address"`
+	BlockNumber int64  `json:"block_number"`
+	NodeId      int64  `json:"node_id"`
+}
+
+func notify(tx *sql.Tx, channel string, payload interface{}) error {
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, channel, string(message))
+	return err
+}
+
+// notifyLogs sends the notifications of logs inserted in the transaction,
+// which every path inserting logs calls so that listeners see all of them.
+func notifyLogs(tx *sql.Tx, logs []core.Log, nodeId int64) error {
+	for _, notification := range logNotifications(logs, nodeId) {
+		err := notify(tx, LogsChannel, notification)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// receiptLogs are the logs of the receipts in the blocks.
+func receiptLogs(blocks ...core.Block) []core.Log {
+	var logs []core.Log
+	for _, block := range blocks {
+		for _, transaction := range block.Transactions {
+			if hasReceipt(transaction) {
+				logs = append(logs, transaction.Receipt.Logs...)
+			}
+		}
+	}
+	return logs
+}
+
+// logNotifications has one notification per contract and block, however
+// many logs the contract emitted in the block.
+func logNotifications(logs []core.Log, nodeId int64) []LogNotification {
+	var notifications []LogNotification
+	seen := make(map[LogNotification]bool)
+	for _, log := range logs {
+		notification := LogNotification{Address: log.Address, BlockNumber: log.BlockNumber, NodeId: nodeId}
+		if !seen[notification] {
+			seen[notification] = true
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications
+}
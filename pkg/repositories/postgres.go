@@ -5,14 +5,20 @@ import (
 
 	"context"
 
+	"encoding/json"
+
 	"errors"
 
 	"fmt"
 
+	"math/big"
+
+	"strings"
+
 	"github.com/vulcanize/vulcanizedb/pkg/config"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type BlockStatus int
@@ -38,6 +44,38 @@ var ErrBlockDoesNotExist = func(blockNumber int64) error {
 	return errors.New(fmt.Sprintf("Block number %d does not exist", blockNumber))
 }
 
+var ErrBlockHashDoesNotExist = func(blockHash string) error {
+	return errors.New(fmt.Sprintf("Block %v does not exist", blockHash))
+}
+
+var ErrLogDoesNotExist = func(blockNumber int64, index int64) error {
+	return errors.New(fmt.Sprintf("Log %d in block number %d does not exist", index, blockNumber))
+}
+
+var ErrLogCheckpointDoesNotExist = func(contractHash string) error {
+	return errors.New(fmt.Sprintf("Log checkpoint for contract %v does not exist", contractHash))
+}
+
+var ErrDecodedEventDoesNotExist = func(blockNumber int64, index int64) error {
+	return errors.New(fmt.Sprintf("Decoded event for log %d in block number %d does not exist", index, blockNumber))
+}
+
+var ErrDeploymentReceiptDoesNotExist = func(contractHash string) error {
+	return errors.New(fmt.Sprintf("Receipt creating contract %v does not exist", contractHash))
+}
+
+var ErrContractCreationDoesNotExist = func(contractAddress string) error {
+	return errors.New(fmt.Sprintf("Creation of contract %v does not exist", contractAddress))
+}
+
+var ErrTransactionDoesNotExist = func(txHash string) error {
+	return errors.New(fmt.Sprintf("Transaction %v does not exist", txHash))
+}
+
+var ErrReceiptDoesNotExist = func(txHash string) error {
+	return errors.New(fmt.Sprintf("Receipt for transaction %v does not exist", txHash))
+}
+
 func NewPostgres(databaseConfig config.Database, node core.Node) (Postgres, error) {
 	connectString := config.DbConnectionString(databaseConfig)
 	db, err := sqlx.Connect("postgres", connectString)
@@ -64,9 +102,10 @@ func (repository Postgres) CreateLogs(logs []core.Log) error {
 	tx, _ := repository.Db.BeginTx(context.Background(), nil)
 	for _, tlog := range logs {
 		_, err := tx.Exec(
-			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data)
-                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-                ON CONFLICT (index, block_number)
+			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, node_id, block_id)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+                        (SELECT id FROM blocks WHERE node_id = $10 AND block_number = $1))
+                ON CONFLICT (node_id, block_number, index)
                   DO UPDATE
                     SET block_number = $1,
                    	    address = $2,
@@ -76,15 +115,21 @@ func (repository Postgres) CreateLogs(logs []core.Log) error {
                    	    topic1 = $6,
                    	    topic2 = $7,
                    	    topic3 = $8,
-                   	    data = $9
+                   	    data = $9,
+                   	    block_id = EXCLUDED.block_id
                 `,
-			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data,
+			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data, repository.nodeId,
 		)
 		if err != nil {
 			tx.Rollback()
 			return ErrDBInsertFailed
 		}
 	}
+	err := notifyLogs(tx, logs, repository.nodeId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
 	tx.Commit()
 	return nil
 }
@@ -101,11 +146,456 @@ func (repository Postgres) FindLogs(address string, blockNumber int64) []core.Lo
 					  topic3,
 					  data
 				FROM logs
-				WHERE address = $1 AND block_number = $2
-				ORDER BY block_number DESC`, address, blockNumber)
+				WHERE address = $1 AND block_number = $2 AND node_id = $3
+				ORDER BY block_number DESC`, address, blockNumber, repository.nodeId)
+	return repository.loadLogs(logRows)
+}
+
+func (repository Postgres) FindLogsInRange(address string, startingBlockNumber int64, endingBlockNumber int64) []core.Log {
+	logRows, _ := repository.Db.Query(
+		`SELECT block_number,
+					  address,
+					  tx_hash,
+					  index,
+					  topic0,
+					  topic1,
+					  topic2,
+					  topic3,
+					  data
+				FROM logs
+				WHERE address = $1 AND block_number BETWEEN $2 AND $3 AND node_id = $4
+				ORDER BY block_number, index`, address, startingBlockNumber, endingBlockNumber, repository.nodeId)
 	return repository.loadLogs(logRows)
 }
 
+// FindLogsPage returns at most limit logs of the address within the block
+// range that come after the position, in block and index order.
+func (repository Postgres) FindLogsPage(address string, startingBlockNumber int64, endingBlockNumber int64, after core.LogPosition, limit int) []core.Log {
+	logRows, _ := repository.Db.Query(
+		`SELECT block_number,
+					  address,
+					  tx_hash,
+					  index,
+					  topic0,
+					  topic1,
+					  topic2,
+					  topic3,
+					  data
+				FROM logs
+				WHERE address = $1 AND block_number BETWEEN $2 AND $3 AND node_id = $4
+				  AND (block_number, index) > ($5, $6)
+				ORDER BY block_number, index
+				LIMIT $7`, address, startingBlockNumber, endingBlockNumber, repository.nodeId, after.BlockNumber, after.Index, limit)
+	return repository.loadLogs(logRows)
+}
+
+func (repository Postgres) CreateDecodedEvents(events []core.DecodedEvent) error {
+	tx, _ := repository.Db.BeginTx(context.Background(), nil)
+	for _, event := range events {
+		arguments, err := json.Marshal(event.Arguments)
+		if err != nil {
+			tx.Rollback()
+			return ErrDBInsertFailed
+		}
+		_, err = tx.Exec(
+			`INSERT INTO decoded_events (log_id, name, arguments)
+                VALUES ((SELECT id FROM logs WHERE node_id = $1 AND block_number = $2 AND index = $3), $4, $5)
+                ON CONFLICT (log_id)
+                  DO UPDATE
+                    SET name = $4,
+                        arguments = $5
+                `,
+			repository.nodeId, event.BlockNumber, event.LogIndex, event.Name, string(arguments),
+		)
+		if err != nil {
+			tx.Rollback()
+			return ErrDBInsertFailed
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (repository Postgres) FindDecodedEvents(address string, eventName string) []core.DecodedEvent {
+	var decodedEvents []core.DecodedEvent
+	rows, _ := repository.Db.Query(
+		`SELECT logs.address,
+                        logs.block_number,
+                        logs.index,
+                        logs.tx_hash,
+                        decoded_events.name,
+                        decoded_events.arguments
+                 FROM decoded_events
+                   JOIN logs ON logs.id = decoded_events.log_id
+                 WHERE logs.address = $1 AND decoded_events.name = $2 AND logs.node_id = $3
+                 ORDER BY logs.block_number, logs.index`, address, eventName, repository.nodeId)
+	for rows.Next() {
+		var decodedEvent core.DecodedEvent
+		var arguments []byte
+		rows.Scan(&decodedEvent.Address, &decodedEvent.BlockNumber, &decodedEvent.LogIndex, &decodedEvent.TxHash, &decodedEvent.Name, &arguments)
+		json.Unmarshal(arguments, &decodedEvent.Arguments)
+		decodedEvents = append(decodedEvents, decodedEvent)
+	}
+	return decodedEvents
+}
+
+func (repository Postgres) FindDecodedEvent(blockNumber int64, logIndex int64) (core.DecodedEvent, error) {
+	var decodedEvent core.DecodedEvent
+	var arguments []byte
+	err := repository.Db.QueryRow(
+		`SELECT logs.address,
+                        logs.block_number,
+                        logs.index,
+                        logs.tx_hash,
+                        decoded_events.name,
+                        decoded_events.arguments
+                 FROM decoded_events
+                   JOIN logs ON logs.id = decoded_events.log_id
+                 WHERE logs.block_number = $1 AND logs.index = $2 AND logs.node_id = $3`, blockNumber, logIndex, repository.nodeId).
+		Scan(&decodedEvent.Address, &decodedEvent.BlockNumber, &decodedEvent.LogIndex, &decodedEvent.TxHash, &decodedEvent.Name, &arguments)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return core.DecodedEvent{}, ErrDecodedEventDoesNotExist(blockNumber, logIndex)
+		default:
+			return core.DecodedEvent{}, err
+		}
+	}
+	json.Unmarshal(arguments, &decodedEvent.Arguments)
+	return decodedEvent, nil
+}
+
+func (repository Postgres) CreateDecodedCalls(calls []core.DecodedCall) error {
+	tx, _ := repository.Db.BeginTx(context.Background(), nil)
+	for _, call := range calls {
+		arguments, err := json.Marshal(call.Arguments)
+		if err != nil {
+			tx.Rollback()
+			return ErrDBInsertFailed
+		}
+		_, err = tx.Exec(
+			`INSERT INTO decoded_calls (transaction_id, method_name, arguments)
+                VALUES ((SELECT transactions.id
+                         FROM transactions
+                           JOIN blocks ON blocks.id = transactions.block_id
+                         WHERE transactions.tx_hash = $1 AND blocks.node_id = $2), $3, $4)
+                ON CONFLICT (transaction_id)
+                  DO UPDATE
+                    SET method_name = $3,
+                        arguments = $4
+                `,
+			call.TxHash, repository.nodeId, call.Method, string(arguments),
+		)
+		if err != nil {
+			tx.Rollback()
+			return ErrDBInsertFailed
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (repository Postgres) FindDecodedCalls(contractHash string, methodName string) []core.DecodedCall {
+	var decodedCalls []core.DecodedCall
+	rows, _ := repository.Db.Query(
+		`SELECT transactions.tx_hash,
+                        transactions.tx_to,
+                        transactions.tx_from,
+                        decoded_calls.method_name,
+                        decoded_calls.arguments
+                 FROM decoded_calls
+                   JOIN transactions ON transactions.id = decoded_calls.transaction_id
+                   JOIN blocks ON blocks.id = transactions.block_id
+                 WHERE transactions.tx_to = lower($1) AND decoded_calls.method_name = $2 AND blocks.node_id = $3
+                 ORDER BY blocks.block_number, transactions.tx_hash`, contractHash, methodName, repository.nodeId)
+	for rows.Next() {
+		var decodedCall core.DecodedCall
+		var arguments []byte
+		rows.Scan(&decodedCall.TxHash, &decodedCall.To, &decodedCall.From, &decodedCall.Method, &arguments)
+		json.Unmarshal(arguments, &decodedCall.Arguments)
+		decodedCalls = append(decodedCalls, decodedCall)
+	}
+	return decodedCalls
+}
+
+// CreateEventTable creates a table with a typed column per event argument and
+// fills it from the decoded events of the contract. The table is registered so
+// that UpdateEventTables can keep it current as more logs are decoded, reading
+// only the events decoded since the last update.
+func (repository Postgres) CreateEventTable(table core.EventTable) error {
+	columns, err := json.Marshal(table.Columns)
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	tx, _ := repository.Db.BeginTx(context.Background(), nil)
+	_, err = tx.Exec(createEventTableStatement(table))
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	_, err = tx.Exec(
+		`INSERT INTO event_tables (name, contract_hash, event_name, columns)
+                VALUES ($1, $2, $3, $4)
+                ON CONFLICT (name)
+                  DO UPDATE
+                    SET contract_hash = $2,
+                        event_name = $3,
+                        columns = $4,
+                        last_decoded_event_id = 0
+                `,
+		table.Name, table.ContractHash, table.EventName, string(columns))
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	tx.Commit()
+	return repository.populateEventTable(table)
+}
+
+func (repository Postgres) FindEventTables(contractHash string) []core.EventTable {
+	var eventTables []core.EventTable
+	rows, _ := repository.Db.Query(
+		`SELECT name,
+                        contract_hash,
+                        event_name,
+                        columns
+                 FROM event_tables
+                 WHERE lower(contract_hash) = lower($1)
+                 ORDER BY name`, contractHash)
+	for rows.Next() {
+		var eventTable core.EventTable
+		var columns []byte
+		rows.Scan(&eventTable.Name, &eventTable.ContractHash, &eventTable.EventName, &columns)
+		json.Unmarshal(columns, &eventTable.Columns)
+		eventTables = append(eventTables, eventTable)
+	}
+	return eventTables
+}
+
+// FindUndecodedLogs returns the logs of a contract that were saved without
+// being decoded, such as those ingested before its abi was known.
+func (repository Postgres) FindUndecodedLogs(contractHash string) []core.Log {
+	logRows, _ := repository.Db.Query(
+		`SELECT block_number,
+                        address,
+                        tx_hash,
+                        index,
+                        topic0,
+                        topic1,
+                        topic2,
+                        topic3,
+                        data
+                 FROM logs
+                   LEFT JOIN decoded_events ON decoded_events.log_id = logs.id
+                 WHERE lower(address) = lower($1) AND node_id = $2 AND decoded_events.id IS NULL
+                 ORDER BY block_number, index`, contractHash, repository.nodeId)
+	return repository.loadLogs(logRows)
+}
+
+func (repository Postgres) UpdateEventTables(contractHash string) error {
+	for _, eventTable := range repository.FindEventTables(contractHash) {
+		err := repository.populateEventTable(eventTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// populateEventTable adds the events decoded after the last decoded event
+// the table was filled from. The mark follows decoded events rather than
+// logs, because the logs of a contract saved before its abi was known are
+// decoded after newer logs.
+func (repository Postgres) populateEventTable(table core.EventTable) error {
+	tx, err := repository.Db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	var lastDecodedEventId, maxDecodedEventId int64
+	err = tx.QueryRow(
+		`SELECT last_decoded_event_id
+                 FROM event_tables
+                 WHERE name = $1
+                 FOR UPDATE`, table.Name).Scan(&lastDecodedEventId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = tx.QueryRow(`SELECT COALESCE(max(id), 0) FROM decoded_events`).Scan(&maxDecodedEventId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	_, err = tx.Exec(populateEventTableStatement(table),
+		table.ContractHash, table.EventName, repository.nodeId, lastDecodedEventId, maxDecodedEventId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	_, err = tx.Exec(`UPDATE event_tables SET last_decoded_event_id = $2 WHERE name = $1`, table.Name, maxDecodedEventId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = tx.Commit()
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	return nil
+}
+
+func createEventTableStatement(table core.EventTable) string {
+	columnDefinitions := []string{
+		"log_id INTEGER PRIMARY KEY REFERENCES logs (id) ON DELETE CASCADE",
+		"block_number BIGINT",
+		"log_index BIGINT",
+		"tx_hash VARCHAR(66)",
+	}
+	for _, column := range table.Columns {
+		columnDefinitions = append(columnDefinitions,
+			fmt.Sprintf("%s %s", pq.QuoteIdentifier(column.Name), eventColumnType(column)))
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)",
+		pq.QuoteIdentifier(table.Name), strings.Join(columnDefinitions, ", "))
+}
+
+func populateEventTableStatement(table core.EventTable) string {
+	columnNames := append([]string{}, core.EventTableLogColumns...)
+	columnValues := []string{"logs.id", "logs.block_number", "logs.index", "logs.tx_hash"}
+	for i, column := range table.Columns {
+		columnNames = append(columnNames, pq.QuoteIdentifier(column.Name))
+		columnValues = append(columnValues,
+			fmt.Sprintf("(decoded_events.arguments -> %d ->> 'value')::%s", i, eventColumnType(column)))
+	}
+	return fmt.Sprintf(
+		`INSERT INTO %s (%s)
+                SELECT %s
+                FROM decoded_events
+                  JOIN logs ON logs.id = decoded_events.log_id
+                WHERE lower(logs.address) = lower($1) AND decoded_events.name = $2 AND logs.node_id = $3
+                  AND decoded_events.id > $4 AND decoded_events.id <= $5
+                ON CONFLICT (log_id) DO NOTHING`,
+		pq.QuoteIdentifier(table.Name), strings.Join(columnNames, ", "), strings.Join(columnValues, ", "))
+}
+
+// Indexed arguments of dynamic types only appear in logs as their hash.
+func eventColumnType(column core.EventTableColumn) string {
+	switch {
+	case column.Type == "address":
+		return "VARCHAR(42)"
+	case column.Type == "bool":
+		return "BOOLEAN"
+	case strings.HasSuffix(column.Type, "]"):
+		if column.Indexed {
+			return "VARCHAR(66)"
+		}
+		return "TEXT"
+	case strings.HasPrefix(column.Type, "int"), strings.HasPrefix(column.Type, "uint"):
+		return "NUMERIC"
+	case column.Type == "string", column.Type == "bytes":
+		if column.Indexed {
+			return "VARCHAR(66)"
+		}
+		return "TEXT"
+	case strings.HasPrefix(column.Type, "bytes"):
+		return "VARCHAR(66)"
+	}
+	return "TEXT"
+}
+
+func (repository Postgres) FindReceipt(txHash string) (core.Receipt, error) {
+	row := repository.Db.QueryRow(
+		`SELECT receipts.id,
+                        contract_address,
+                        receipts.tx_hash,
+                        cumulative_gas_used,
+                        gas_used,
+                        state_root,
+                        status,
+                        bloom
+                 FROM receipts
+                   JOIN transactions ON transactions.id = receipts.transaction_id
+                   JOIN blocks ON blocks.id = transactions.block_id
+                 WHERE receipts.tx_hash = $1 AND blocks.node_id = $2`, txHash, repository.nodeId)
+	receipt, err := repository.loadReceipt(row)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return core.Receipt{}, ErrReceiptDoesNotExist(txHash)
+		default:
+			return core.Receipt{}, err
+		}
+	}
+	return receipt, nil
+}
+
+func (repository Postgres) FindDeploymentBlockNumber(contractHash string) (int64, error) {
+	var blockNumber int64
+	err := repository.Db.Get(&blockNumber,
+		`SELECT blocks.block_number
+                 FROM receipts
+                   JOIN transactions ON transactions.id = receipts.transaction_id
+                   JOIN blocks ON blocks.id = transactions.block_id
+                 WHERE lower(receipts.contract_address) = lower($1) AND blocks.node_id = $2
+                 ORDER BY blocks.block_number
+                 LIMIT 1`, contractHash, repository.nodeId)
+	if err != nil {
+		return 0, ErrDeploymentReceiptDoesNotExist(contractHash)
+	}
+	return blockNumber, nil
+}
+
+func (repository Postgres) FindContractCreation(contractAddress string) (core.ContractCreation, error) {
+	rows, _ := repository.Db.Query(
+		`SELECT blocks.block_number,
+                        transactions.tx_hash,
+                        contract_creations.creator_address,
+                        contract_creations.contract_address,
+                        contract_creations.code_hash
+                 FROM contract_creations
+                   JOIN transactions ON transactions.id = contract_creations.transaction_id
+                   JOIN blocks ON blocks.id = transactions.block_id
+                 WHERE lower(contract_creations.contract_address) = lower($1) AND blocks.node_id = $2
+                 ORDER BY blocks.block_number
+                 LIMIT 1`, contractAddress, repository.nodeId)
+	creations := loadContractCreations(rows)
+	if len(creations) == 0 {
+		return core.ContractCreation{}, ErrContractCreationDoesNotExist(contractAddress)
+	}
+	return creations[0], nil
+}
+
+func (repository Postgres) FindContractCreationsByCreator(creatorAddress string) []core.ContractCreation {
+	rows, _ := repository.Db.Query(
+		`SELECT blocks.block_number,
+                        transactions.tx_hash,
+                        contract_creations.creator_address,
+                        contract_creations.contract_address,
+                        contract_creations.code_hash
+                 FROM contract_creations
+                   JOIN transactions ON transactions.id = contract_creations.transaction_id
+                   JOIN blocks ON blocks.id = transactions.block_id
+                 WHERE lower(contract_creations.creator_address) = lower($1) AND blocks.node_id = $2
+                 ORDER BY blocks.block_number, transactions.id`, creatorAddress, repository.nodeId)
+	return loadContractCreations(rows)
+}
+
+func loadContractCreations(rows *sql.Rows) []core.ContractCreation {
+	var creations []core.ContractCreation
+	if rows == nil {
+		return creations
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var creation core.ContractCreation
+		var codeHash sql.NullString
+		rows.Scan(&creation.BlockNumber, &creation.TxHash, &creation.CreatorAddress, &creation.ContractAddress, &codeHash)
+		creation.CodeHash = codeHash.String
+		creations = append(creations, creation)
+	}
+	return creations
+}
+
 func (repository *Postgres) CreateNode(node *core.Node) error {
 	var nodeId int64
 	err := repository.Db.QueryRow(
@@ -130,12 +620,13 @@ func (repository Postgres) CreateContract(contract core.Contract) error {
 		abiToInsert = &abi
 	}
 	_, err := repository.Db.Exec(
-		`INSERT INTO watched_contracts (contract_hash, contract_abi)
-				VALUES ($1, $2)
+		`INSERT INTO watched_contracts (contract_hash, contract_abi, deployment_block)
+				VALUES ($1, $2, NULLIF($3, 0))
 				ON CONFLICT (contract_hash)
 				  DO UPDATE
-					SET contract_hash = $1, contract_abi = $2
-				`, contract.Hash, abiToInsert)
+					SET contract_hash = $1, contract_abi = $2,
+					    deployment_block = COALESCE(EXCLUDED.deployment_block, watched_contracts.deployment_block)
+				`, contract.Hash, abiToInsert, contract.DeploymentBlock)
 	if err != nil {
 		return ErrDBInsertFailed
 	}
@@ -155,16 +646,140 @@ func (repository Postgres) ContractExists(contractHash string) bool {
 func (repository Postgres) FindContract(contractHash string) (core.Contract, error) {
 	var hash string
 	var abi string
+	var deploymentBlock int64
 	contract := repository.Db.QueryRow(
-		`SELECT contract_hash, contract_abi FROM watched_contracts WHERE contract_hash=$1`, contractHash)
-	err := contract.Scan(&hash, &abi)
+		`SELECT contract_hash, contract_abi, COALESCE(deployment_block, 0) FROM watched_contracts WHERE contract_hash=$1`, contractHash)
+	err := contract.Scan(&hash, &abi, &deploymentBlock)
 	if err == sql.ErrNoRows {
 		return core.Contract{}, ErrContractDoesNotExist(contractHash)
 	}
-	savedContract := repository.addTransactions(core.Contract{Hash: hash, Abi: abi})
+	savedContract := repository.addTransactions(core.Contract{Hash: hash, Abi: abi, DeploymentBlock: deploymentBlock})
 	return savedContract, nil
 }
 
+func (repository Postgres) FindWatchedContracts() []core.Contract {
+	var contracts []core.Contract
+	rows, _ := repository.Db.Query(
+		`SELECT contract_hash, contract_abi, COALESCE(deployment_block, 0) FROM watched_contracts ORDER BY contract_hash`)
+	for rows.Next() {
+		var hash string
+		var abi sql.NullString
+		var deploymentBlock int64
+		rows.Scan(&hash, &abi, &deploymentBlock)
+		contracts = append(contracts, core.Contract{Hash: hash, Abi: abi.String, DeploymentBlock: deploymentBlock})
+	}
+	return contracts
+}
+
+func (repository Postgres) CreateContractState(states []core.ContractState) error {
+	tx, _ := repository.Db.BeginTx(context.Background(), nil)
+	for _, state := range states {
+		_, err := tx.Exec(
+			`INSERT INTO contract_state (node_id, block_id, contract_hash, block_number, attribute_name, value)
+                VALUES ($1, (SELECT id FROM blocks WHERE node_id = $1 AND block_number = $3), $2, $3, $4, $5)
+                ON CONFLICT (node_id, contract_hash, block_number, attribute_name)
+                  DO UPDATE
+                    SET block_id = EXCLUDED.block_id,
+                        value = $5
+                `,
+			repository.nodeId, state.ContractHash, state.BlockNumber, state.AttributeName, state.Value,
+		)
+		if err != nil {
+			tx.Rollback()
+			return ErrDBInsertFailed
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (repository Postgres) FindContractState(contractHash string, attributeName string) []core.ContractState {
+	var states []core.ContractState
+	rows, _ := repository.Db.Query(
+		`SELECT contract_hash,
+                        block_number,
+                        attribute_name,
+                        value
+                 FROM contract_state
+                 WHERE contract_hash = $1 AND attribute_name = $2 AND node_id = $3
+                 ORDER BY block_number`, contractHash, attributeName, repository.nodeId)
+	for rows.Next() {
+		var state core.ContractState
+		rows.Scan(&state.ContractHash, &state.BlockNumber, &state.AttributeName, &state.Value)
+		states = append(states, state)
+	}
+	return states
+}
+
+func (repository Postgres) FindLogCheckpoint(contractHash string, topicsKey string) (int64, error) {
+	var blockNumber int64
+	err := repository.Db.Get(&blockNumber,
+		`SELECT block_number FROM log_checkpoints WHERE node_id = $1 AND contract_hash = $2 AND topics_key = $3`,
+		repository.nodeId, contractHash, topicsKey)
+	if err != nil {
+		return 0, ErrLogCheckpointDoesNotExist(contractHash)
+	}
+	return blockNumber, nil
+}
+
+// UpdateLogCheckpoint never moves a checkpoint back, so retrying an earlier
+// range does not cause later ranges to be scanned again.
+func (repository Postgres) UpdateLogCheckpoint(contractHash string, topicsKey string, blockNumber int64) error {
+	_, err := repository.Db.Exec(
+		`INSERT INTO log_checkpoints (node_id, contract_hash, topics_key, block_number)
+                VALUES ($1, $2, $3, $4)
+                ON CONFLICT (node_id, contract_hash, topics_key)
+                  DO UPDATE
+                    SET block_number = GREATEST(log_checkpoints.block_number, EXCLUDED.block_number)`,
+		repository.nodeId, contractHash, topicsKey, blockNumber)
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	return nil
+}
+
+func (repository Postgres) CreateFailedLogRange(logRange core.LogRange) error {
+	_, err := repository.Db.Exec(
+		`INSERT INTO failed_log_ranges (node_id, contract_hash, topics_key, starting_block_number, ending_block_number)
+                VALUES ($1, $2, $3, $4, $5)
+                ON CONFLICT (node_id, contract_hash, topics_key, starting_block_number, ending_block_number)
+                  DO NOTHING`,
+		repository.nodeId, logRange.ContractHash, logRange.TopicsKey, logRange.StartingBlockNumber, logRange.EndingBlockNumber)
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	return nil
+}
+
+func (repository Postgres) FindFailedLogRanges(contractHash string, topicsKey string) []core.LogRange {
+	var logRanges []core.LogRange
+	rows, _ := repository.Db.Query(
+		`SELECT contract_hash,
+                        topics_key,
+                        starting_block_number,
+                        ending_block_number
+                 FROM failed_log_ranges
+                 WHERE node_id = $1 AND contract_hash = $2 AND topics_key = $3
+                 ORDER BY starting_block_number`, repository.nodeId, contractHash, topicsKey)
+	for rows.Next() {
+		var logRange core.LogRange
+		rows.Scan(&logRange.ContractHash, &logRange.TopicsKey, &logRange.StartingBlockNumber, &logRange.EndingBlockNumber)
+		logRanges = append(logRanges, logRange)
+	}
+	return logRanges
+}
+
+func (repository Postgres) DeleteFailedLogRange(logRange core.LogRange) error {
+	_, err := repository.Db.Exec(
+		`DELETE FROM failed_log_ranges
+                 WHERE node_id = $1 AND contract_hash = $2 AND topics_key = $3 AND starting_block_number = $4 AND ending_block_number = $5`,
+		repository.nodeId, logRange.ContractHash, logRange.TopicsKey, logRange.StartingBlockNumber, logRange.EndingBlockNumber)
+	if err != nil {
+		return ErrDBDeleteFailed
+	}
+	return nil
+}
+
 func (repository Postgres) MaxBlockNumber() int64 {
 	var highestBlockNumber int64
 	repository.Db.Get(&highestBlockNumber, `SELECT MAX(block_number) FROM blocks`)
@@ -213,6 +828,83 @@ func (repository Postgres) FindBlockByNumber(blockNumber int64) (core.Block, err
 	return savedBlock, nil
 }
 
+func (repository Postgres) FindBlockByHash(blockHash string) (core.Block, error) {
+	blockRows := repository.Db.QueryRow(
+		`SELECT id,
+                       block_number,
+                       block_gaslimit,
+                       block_gasused,
+                       block_time,
+                       block_difficulty,
+                       block_hash,
+                       block_nonce,
+                       block_parenthash,
+                       block_size,
+                       uncle_hash,
+                       is_final
+               FROM blocks
+               WHERE node_id = $1 AND block_hash = $2`, repository.nodeId, blockHash)
+	savedBlock, err := repository.loadBlock(blockRows)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return core.Block{}, ErrBlockHashDoesNotExist(blockHash)
+		default:
+			return savedBlock, err
+		}
+	}
+	return savedBlock, nil
+}
+
+// FindTransactions returns the transactions sent to or from the address,
+// most recent block first.
+func (repository Postgres) FindTransactions(address string, limit int, offset int) []core.Transaction {
+	transactionRows, _ := repository.Db.Query(`
+            SELECT tx_hash,
+                   tx_nonce,
+                   tx_to,
+                   tx_from,
+                   tx_gaslimit,
+                   tx_gasprice,
+                   tx_value,
+                   input_data
+            FROM transactions
+            INNER JOIN blocks ON blocks.id = transactions.block_id
+            WHERE blocks.node_id = $1 AND (tx_to = lower($2) OR tx_from = lower($2))
+            ORDER BY blocks.block_number DESC, tx_hash
+            LIMIT $3 OFFSET $4`, repository.nodeId, address, limit, offset)
+	return repository.loadTransactions(transactionRows)
+}
+
+// TransactionCount returns the number of transactions sent to or from the
+// address.
+func (repository Postgres) TransactionCount(address string) int {
+	var count int
+	repository.Db.Get(&count, `
+            SELECT COUNT(*)
+            FROM transactions
+            INNER JOIN blocks ON blocks.id = transactions.block_id
+            WHERE blocks.node_id = $1 AND (tx_to = lower($2) OR tx_from = lower($2))`, repository.nodeId, address)
+	return count
+}
+
+func (repository Postgres) FindOrphanedBlocks(blockNumber int64) []core.Block {
+	var orphanedBlocks []core.Block
+	rows, _ := repository.Db.Query(
+		`SELECT block_number,
+                        block_hash,
+                        block_parenthash
+                 FROM orphaned_blocks
+                 WHERE node_id = $1 AND block_number = $2
+                 ORDER BY id`, repository.nodeId, blockNumber)
+	for rows.Next() {
+		var orphanedBlock core.Block
+		rows.Scan(&orphanedBlock.Number, &orphanedBlock.Hash, &orphanedBlock.ParentHash)
+		orphanedBlocks = append(orphanedBlocks, orphanedBlock)
+	}
+	return orphanedBlocks
+}
+
 func (repository Postgres) BlockCount() int {
 	var count int
 	repository.Db.Get(&count, `SELECT COUNT(*) FROM blocks`)
@@ -259,7 +951,7 @@ func (repository Postgres) insertBlock(block core.Block) error {
 			    (node_id, block_number, block_gaslimit, block_gasused, block_time, block_difficulty, block_hash, block_nonce, block_parenthash, block_size, uncle_hash, is_final)
 			    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		        RETURNING id `,
-		repository.nodeId, block.Number, block.GasLimit, block.GasUsed, block.Time, block.Difficulty, block.Hash, block.Nonce, block.ParentHash, block.Size, block.UncleHash, block.IsFinal).
+		repository.nodeId, block.Number, block.GasLimit, block.GasUsed, block.Time, bigIntToString(block.Difficulty), block.Hash, block.Nonce, block.ParentHash, block.Size, block.UncleHash, block.IsFinal).
 		Scan(&blockId)
 	if err != nil {
 		tx.Rollback()
@@ -270,16 +962,91 @@ func (repository Postgres) insertBlock(block core.Block) error {
 		tx.Rollback()
 		return ErrDBInsertFailed
 	}
+	err = repository.linkLogsToBlock(tx, blockId, block.Number)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = repository.linkContractStateToBlock(tx, blockId, block.Number)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = notify(tx, BlocksChannel, BlockNotification{Action: BlockInserted, Number: block.Number, Hash: block.Hash, NodeId: repository.nodeId})
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = notifyLogs(tx, receiptLogs(block), repository.nodeId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
 	tx.Commit()
 	return nil
 }
 
+func (repository Postgres) linkLogsToBlock(tx *sql.Tx, blockId int64, blockNumber int64) error {
+	_, err := tx.Exec(
+		`UPDATE logs
+                SET block_id = $1
+                WHERE node_id = $2 AND block_number = $3`,
+		blockId, repository.nodeId, blockNumber)
+	return err
+}
+
+func (repository Postgres) linkContractStateToBlock(tx *sql.Tx, blockId int64, blockNumber int64) error {
+	_, err := tx.Exec(
+		`UPDATE contract_state
+                SET block_id = $1
+                WHERE node_id = $2 AND block_number = $3`,
+		blockId, repository.nodeId, blockNumber)
+	return err
+}
+
 func (repository Postgres) removeBlock(blockNumber int64) error {
-	_, err := repository.Db.Exec(
-		`DELETE FROM
-				blocks
+	tx, err := repository.Db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return ErrDBDeleteFailed
+	}
+	err = repository.deleteBlock(tx, blockNumber)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return ErrDBDeleteFailed
+	}
+	return nil
+}
+
+// deleteBlock moves the saved block to the orphaned blocks, doing nothing if
+// no block is saved at the number.
+func (repository Postgres) deleteBlock(tx *sql.Tx, blockNumber int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO orphaned_blocks (node_id, block_number, block_hash, block_parenthash)
+				SELECT node_id, block_number, block_hash, block_parenthash
+				FROM blocks
 				WHERE block_number=$1 AND node_id=$2`,
 		blockNumber, repository.nodeId)
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	var blockHash sql.NullString
+	err = tx.QueryRow(
+		`DELETE FROM
+				blocks
+				WHERE block_number=$1 AND node_id=$2
+				RETURNING block_hash`,
+		blockNumber, repository.nodeId).Scan(&blockHash)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return ErrDBDeleteFailed
+	}
+	err = notify(tx, BlocksChannel, BlockNotification{Action: BlockRemoved, Number: blockNumber, Hash: blockHash.String, NodeId: repository.nodeId})
 	if err != nil {
 		return ErrDBDeleteFailed
 	}
@@ -288,11 +1055,96 @@ func (repository Postgres) removeBlock(blockNumber int64) error {
 
 func (repository Postgres) createTransactions(tx *sql.Tx, blockId int64, transactions []core.Transaction) error {
 	for _, transaction := range transactions {
-		_, err := tx.Exec(
+		var transactionId int64
+		err := tx.QueryRow(
 			`INSERT INTO transactions
-           (block_id, tx_hash, tx_nonce, tx_to, tx_from, tx_gaslimit, tx_gasprice, tx_value)
-           VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-			blockId, transaction.Hash, transaction.Nonce, transaction.To, transaction.From, transaction.GasLimit, transaction.GasPrice, transaction.Value)
+           (block_id, tx_hash, tx_nonce, tx_to, tx_from, tx_gaslimit, tx_gasprice, tx_value, input_data)
+           VALUES ($1, $2, $3, lower($4), lower($5), $6, $7, $8, $9)
+           RETURNING id`,
+			blockId, transaction.Hash, transaction.Nonce, transaction.To, transaction.From, transaction.GasLimit, bigIntToString(transaction.GasPrice), bigIntToString(transaction.Value), inputData(transaction)).
+			Scan(&transactionId)
+		if err != nil {
+			return err
+		}
+		if hasReceipt(transaction) {
+			err = repository.createReceipt(tx, blockId, transactionId, transaction.Receipt)
+			if err != nil {
+				return err
+			}
+		}
+		if transaction.CreatesContract() {
+			err = repository.createContractCreation(tx, transactionId, transaction)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func inputData(transaction core.Transaction) *[]byte {
+	if len(transaction.Data) == 0 {
+		return nil
+	}
+	return &transaction.Data
+}
+
+func hasReceipt(transaction core.Transaction) bool {
+	return transaction.Receipt.TxHash != ""
+}
+
+func (repository Postgres) createReceipt(tx *sql.Tx, blockId int64, transactionId int64, receipt core.Receipt) error {
+	var receiptId int64
+	err := tx.QueryRow(
+		`INSERT INTO receipts
+           (transaction_id, contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, bloom)
+           VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+           RETURNING id`,
+		transactionId, receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, receipt.Bloom).
+		Scan(&receiptId)
+	if err != nil {
+		return err
+	}
+	return repository.createReceiptLogs(tx, blockId, receiptId, receipt.Logs)
+}
+
+func (repository Postgres) createContractCreation(tx *sql.Tx, transactionId int64, transaction core.Transaction) error {
+	_, err := tx.Exec(
+		`INSERT INTO contract_creations
+           (transaction_id, creator_address, contract_address, code_hash)
+           VALUES ($1, $2, $3, $4)`,
+		transactionId, transaction.From, transaction.Receipt.ContractAddress, codeHash(transaction))
+	return err
+}
+
+func codeHash(transaction core.Transaction) *string {
+	if transaction.Receipt.ContractCodeHash == "" {
+		return nil
+	}
+	return &transaction.Receipt.ContractCodeHash
+}
+
+func (repository Postgres) createReceiptLogs(tx *sql.Tx, blockId int64, receiptId int64, logs []core.Log) error {
+	for _, tlog := range logs {
+		_, err := tx.Exec(
+			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id, node_id, block_id)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+                ON CONFLICT (node_id, block_number, index)
+                  DO UPDATE
+                    SET block_number = $1,
+                        address = $2,
+                        tx_hash = $3,
+                        index = $4,
+                        topic0 = $5,
+                        topic1 = $6,
+                        topic2 = $7,
+                        topic3 = $8,
+                        data = $9,
+                        receipt_id = $10,
+                        block_id = $12
+                `,
+			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data, receiptId, repository.nodeId, blockId,
+		)
 		if err != nil {
 			return err
 		}
@@ -308,9 +1160,9 @@ func (repository Postgres) loadBlock(blockRows *sql.Row) (core.Block, error) {
 	var blockParentHash string
 	var blockSize int64
 	var blockTime float64
-	var difficulty int64
-	var gasLimit float64
-	var gasUsed float64
+	var difficulty sql.NullString
+	var gasLimit int64
+	var gasUsed int64
 	var uncleHash string
 	var isFinal bool
 	err := blockRows.Scan(&blockId, &blockNumber, &gasLimit, &gasUsed, &blockTime, &difficulty, &blockHash, &blockNonce, &blockParentHash, &blockSize, &uncleHash, &isFinal)
@@ -324,15 +1176,16 @@ func (repository Postgres) loadBlock(blockRows *sql.Row) (core.Block, error) {
 				   tx_from,
 				   tx_gaslimit,
 				   tx_gasprice,
-				   tx_value
+				   tx_value,
+				   input_data
             FROM transactions
             WHERE block_id = $1
             ORDER BY tx_hash`, blockId)
 	transactions := repository.loadTransactions(transactionRows)
 	return core.Block{
-		Difficulty:   difficulty,
-		GasLimit:     int64(gasLimit),
-		GasUsed:      int64(gasUsed),
+		Difficulty:   stringToBigInt(difficulty),
+		GasLimit:     gasLimit,
+		GasUsed:      gasUsed,
 		Hash:         blockHash,
 		Nonce:        blockNonce,
 		Number:       blockNumber,
@@ -345,6 +1198,44 @@ func (repository Postgres) loadBlock(blockRows *sql.Row) (core.Block, error) {
 	}, nil
 }
 
+func (repository Postgres) loadReceipt(receiptRow *sql.Row) (core.Receipt, error) {
+	var receiptId int64
+	var contractAddress string
+	var txHash string
+	var cumulativeGasUsed int64
+	var gasUsed int64
+	var stateRoot string
+	var status int
+	var bloom string
+	err := receiptRow.Scan(&receiptId, &contractAddress, &txHash, &cumulativeGasUsed, &gasUsed, &stateRoot, &status, &bloom)
+	if err != nil {
+		return core.Receipt{}, err
+	}
+	logRows, _ := repository.Db.Query(
+		`SELECT block_number,
+                        address,
+                        tx_hash,
+                        index,
+                        topic0,
+                        topic1,
+                        topic2,
+                        topic3,
+                        data
+                 FROM logs
+                 WHERE receipt_id = $1
+                 ORDER BY index`, receiptId)
+	return core.Receipt{
+		Bloom:             bloom,
+		ContractAddress:   contractAddress,
+		CumulativeGasUsed: cumulativeGasUsed,
+		GasUsed:           gasUsed,
+		Logs:              repository.loadLogs(logRows),
+		StateRoot:         stateRoot,
+		Status:            status,
+		TxHash:            txHash,
+	}, nil
+}
+
 func (repository Postgres) loadLogs(logsRows *sql.Rows) []core.Log {
 	var logs []core.Log
 	for logsRows.Next() {
@@ -379,17 +1270,19 @@ func (repository Postgres) loadTransactions(transactionRows *sql.Rows) []core.Tr
 		var to string
 		var from string
 		var gasLimit int64
-		var gasPrice int64
-		var value int64
-		transactionRows.Scan(&hash, &nonce, &to, &from, &gasLimit, &gasPrice, &value)
+		var gasPrice sql.NullString
+		var value sql.NullString
+		var inputData []byte
+		transactionRows.Scan(&hash, &nonce, &to, &from, &gasLimit, &gasPrice, &value, &inputData)
 		transaction := core.Transaction{
 			Hash:     hash,
+			Data:     inputData,
 			Nonce:    nonce,
 			To:       to,
 			From:     from,
 			GasLimit: gasLimit,
-			GasPrice: gasPrice,
-			Value:    value,
+			GasPrice: stringToBigInt(gasPrice),
+			Value:    stringToBigInt(value),
 		}
 		transactions = append(transactions, transaction)
 	}
@@ -404,11 +1297,27 @@ func (repository Postgres) addTransactions(contract core.Contract) core.Contract
                    tx_from,
                    tx_gaslimit,
                    tx_gasprice,
-                   tx_value
+                   tx_value,
+                   input_data
             FROM transactions
-            WHERE tx_to = $1
+            WHERE tx_to = lower($1)
             ORDER BY block_id DESC`, contract.Hash)
-	transactions := repository.loadTransactions(transactionRows)
-	savedContract := core.Contract{Hash: contract.Hash, Transactions: transactions, Abi: contract.Abi}
-	return savedContract
+	contract.Transactions = repository.loadTransactions(transactionRows)
+	return contract
+}
+
+func bigIntToString(value *big.Int) *string {
+	if value == nil {
+		return nil
+	}
+	valueString := value.String()
+	return &valueString
+}
+
+func stringToBigInt(value sql.NullString) *big.Int {
+	if !value.Valid {
+		return nil
+	}
+	result, _ := new(big.Int).SetString(value.String, 10)
+	return result
 }
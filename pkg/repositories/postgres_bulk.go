@@ -0,0 +1,340 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/lib/pq"
+)
+
+// The bulk path COPYs blocks, transactions, receipts, receipt logs and
+// contract creations into staging tables that are dropped when the
+// transaction commits, then merges them into the real tables with one
+// statement per table.
+const createStagingTables = `
+	CREATE TEMPORARY TABLE staged_blocks (
+		block_number bigint,
+		block_gaslimit numeric,
+		block_gasused numeric,
+		block_time double precision,
+		block_difficulty numeric,
+		block_hash character varying(66),
+		block_nonce character varying(20),
+		block_parenthash character varying(66),
+		block_size bigint,
+		uncle_hash character varying(66),
+		is_final boolean
+	) ON COMMIT DROP;
+	CREATE TEMPORARY TABLE staged_transactions (
+		block_number bigint,
+		position integer,
+		tx_hash character varying(66),
+		tx_nonce numeric,
+		tx_to character varying(66),
+		tx_from character varying(66),
+		tx_gaslimit numeric,
+		tx_gasprice numeric,
+		tx_value numeric,
+		input_data bytea
+	) ON COMMIT DROP;
+	CREATE TEMPORARY TABLE staged_receipts (
+		block_number bigint,
+		contract_address character varying(42),
+		tx_hash character varying(66),
+		cumulative_gas_used numeric,
+		gas_used numeric,
+		state_root character varying(66),
+		status integer,
+		bloom text
+	) ON COMMIT DROP;
+	CREATE TEMPORARY TABLE staged_logs (
+		block_number bigint,
+		receipt_tx_hash character varying(66),
+		address character varying(66),
+		tx_hash character varying(66),
+		index bigint,
+		topic0 character varying(66),
+		topic1 character varying(66),
+		topic2 character varying(66),
+		topic3 character varying(66),
+		data text
+	) ON COMMIT DROP;
+	CREATE TEMPORARY TABLE staged_contract_creations (
+		block_number bigint,
+		tx_hash character varying(66),
+		creator_address character varying(66),
+		contract_address character varying(66),
+		code_hash character varying(66)
+	) ON COMMIT DROP`
+
+// CreateOrUpdateBlocks saves a batch of blocks like CreateOrUpdateBlock, but
+// inserts the new ones with COPY in a single transaction, which also removes
+// the saved blocks they replace. It is meant for backfilling; live blocks go
+// through CreateOrUpdateBlock.
+func (repository Postgres) CreateOrUpdateBlocks(blocks []core.Block) error {
+	blocks, replaced, err := repository.blocksToInsert(blocks)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	return repository.insertBlocks(blocks, replaced)
+}
+
+// blocksToInsert drops the blocks that are already saved with the same hash
+// and returns the numbers of the saved blocks replaced by a different one.
+func (repository Postgres) blocksToInsert(blocks []core.Block) ([]core.Block, []int64, error) {
+	var blockNumbers []int64
+	latest := make(map[int64]int)
+	for i, block := range blocks {
+		if _, ok := latest[block.Number]; !ok {
+			blockNumbers = append(blockNumbers, block.Number)
+		}
+		latest[block.Number] = i
+	}
+	savedHashes := make(map[int64]string)
+	rows, err := repository.Db.Query(
+		`SELECT block_number, block_hash
+                FROM blocks
+                WHERE node_id = $1 AND block_number = ANY($2)`,
+		repository.nodeId, pq.Array(blockNumbers))
+	if err != nil {
+		return nil, nil, ErrDBInsertFailed
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var blockNumber int64
+		var blockHash sql.NullString
+		err = rows.Scan(&blockNumber, &blockHash)
+		if err != nil {
+			return nil, nil, ErrDBInsertFailed
+		}
+		savedHashes[blockNumber] = blockHash.String
+	}
+	if rows.Err() != nil {
+		return nil, nil, ErrDBInsertFailed
+	}
+	var toInsert []core.Block
+	var replaced []int64
+	for _, blockNumber := range blockNumbers {
+		block := blocks[latest[blockNumber]]
+		savedHash, ok := savedHashes[blockNumber]
+		if ok && savedHash == block.Hash {
+			continue
+		}
+		if ok {
+			replaced = append(replaced, blockNumber)
+		}
+		toInsert = append(toInsert, block)
+	}
+	return toInsert, replaced, nil
+}
+
+func (repository Postgres) insertBlocks(blocks []core.Block, replaced []int64) error {
+	tx, err := repository.Db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	for _, blockNumber := range replaced {
+		err = repository.deleteBlock(tx, blockNumber)
+		if err != nil {
+			tx.Rollback()
+			return ErrDBDeleteFailed
+		}
+	}
+	_, err = tx.Exec(createStagingTables)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = stageBlocks(tx, blocks)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = repository.mergeStagedBlocks(tx)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	for _, block := range blocks {
+		err = notify(tx, BlocksChannel, BlockNotification{Action: BlockInserted, Number: block.Number, Hash: block.Hash, NodeId: repository.nodeId})
+		if err != nil {
+			tx.Rollback()
+			return ErrDBInsertFailed
+		}
+	}
+	err = notifyLogs(tx, receiptLogs(blocks...), repository.nodeId)
+	if err != nil {
+		tx.Rollback()
+		return ErrDBInsertFailed
+	}
+	err = tx.Commit()
+	if err != nil {
+		return ErrDBInsertFailed
+	}
+	return nil
+}
+
+func stageBlocks(tx *sql.Tx, blocks []core.Block) error {
+	var blockRows, transactionRows, receiptRows, logRows, creationRows [][]interface{}
+	for _, block := range blocks {
+		blockRows = append(blockRows, []interface{}{
+			block.Number, block.GasLimit, block.GasUsed, block.Time, bigIntToString(block.Difficulty), block.Hash, block.Nonce, block.ParentHash, block.Size, block.UncleHash, block.IsFinal,
+		})
+		for position, transaction := range block.Transactions {
+			transactionRows = append(transactionRows, []interface{}{
+				block.Number, position, transaction.Hash, transaction.Nonce, transaction.To, transaction.From, transaction.GasLimit, bigIntToString(transaction.GasPrice), bigIntToString(transaction.Value), inputData(transaction),
+			})
+			if transaction.CreatesContract() {
+				creationRows = append(creationRows, []interface{}{
+					block.Number, transaction.Hash, transaction.From, transaction.Receipt.ContractAddress, codeHash(transaction),
+				})
+			}
+			if !hasReceipt(transaction) {
+				continue
+			}
+			receipt := transaction.Receipt
+			receiptRows = append(receiptRows, []interface{}{
+				block.Number, receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, receipt.Bloom,
+			})
+			for _, tlog := range receipt.Logs {
+				logRows = append(logRows, []interface{}{
+					block.Number, receipt.TxHash, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data,
+				})
+			}
+		}
+	}
+	err := copyRows(tx, "staged_blocks", blockRows,
+		"block_number", "block_gaslimit", "block_gasused", "block_time", "block_difficulty", "block_hash", "block_nonce", "block_parenthash", "block_size", "uncle_hash", "is_final")
+	if err != nil {
+		return err
+	}
+	err = copyRows(tx, "staged_transactions", transactionRows,
+		"block_number", "position", "tx_hash", "tx_nonce", "tx_to", "tx_from", "tx_gaslimit", "tx_gasprice", "tx_value", "input_data")
+	if err != nil {
+		return err
+	}
+	err = copyRows(tx, "staged_receipts", receiptRows,
+		"block_number", "contract_address", "tx_hash", "cumulative_gas_used", "gas_used", "state_root", "status", "bloom")
+	if err != nil {
+		return err
+	}
+	err = copyRows(tx, "staged_logs", logRows,
+		"block_number", "receipt_tx_hash", "address", "tx_hash", "index", "topic0", "topic1", "topic2", "topic3", "data")
+	if err != nil {
+		return err
+	}
+	return copyRows(tx, "staged_contract_creations", creationRows,
+		"block_number", "tx_hash", "creator_address", "contract_address", "code_hash")
+}
+
+func copyRows(tx *sql.Tx, table string, rows [][]interface{}, columns ...string) error {
+	statement, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		_, err = statement.Exec(row...)
+		if err != nil {
+			statement.Close()
+			return err
+		}
+	}
+	_, err = statement.Exec()
+	if err != nil {
+		statement.Close()
+		return err
+	}
+	return statement.Close()
+}
+
+func (repository Postgres) mergeStagedBlocks(tx *sql.Tx) error {
+	_, err := tx.Exec(
+		`INSERT INTO blocks
+                (node_id, block_number, block_gaslimit, block_gasused, block_time, block_difficulty, block_hash, block_nonce, block_parenthash, block_size, uncle_hash, is_final)
+                SELECT $1, block_number, block_gaslimit, block_gasused, block_time, block_difficulty, block_hash, block_nonce, block_parenthash, block_size, uncle_hash, is_final
+                FROM staged_blocks
+                ORDER BY block_number`,
+		repository.nodeId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO transactions
+                (block_id, tx_hash, tx_nonce, tx_to, tx_from, tx_gaslimit, tx_gasprice, tx_value, input_data)
+                SELECT blocks.id, st.tx_hash, st.tx_nonce, lower(st.tx_to), lower(st.tx_from), st.tx_gaslimit, st.tx_gasprice, st.tx_value, st.input_data
+                FROM staged_transactions st
+                JOIN blocks ON blocks.node_id = $1 AND blocks.block_number = st.block_number
+                ORDER BY st.block_number, st.position`,
+		repository.nodeId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO receipts
+                (transaction_id, contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, bloom)
+                SELECT transactions.id, sr.contract_address, sr.tx_hash, sr.cumulative_gas_used, sr.gas_used, sr.state_root, sr.status, sr.bloom
+                FROM staged_receipts sr
+                JOIN blocks ON blocks.node_id = $1 AND blocks.block_number = sr.block_number
+                JOIN transactions ON transactions.block_id = blocks.id AND transactions.tx_hash = sr.tx_hash
+                ORDER BY transactions.id`,
+		repository.nodeId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO contract_creations
+                (transaction_id, creator_address, contract_address, code_hash)
+                SELECT transactions.id, scc.creator_address, scc.contract_address, scc.code_hash
+                FROM staged_contract_creations scc
+                JOIN blocks ON blocks.node_id = $1 AND blocks.block_number = scc.block_number
+                JOIN transactions ON transactions.block_id = blocks.id AND transactions.tx_hash = scc.tx_hash
+                ORDER BY transactions.id`,
+		repository.nodeId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id, node_id, block_id)
+                SELECT sl.block_number, sl.address, sl.tx_hash, sl.index, sl.topic0, sl.topic1, sl.topic2, sl.topic3, sl.data, receipts.id, $1, blocks.id
+                FROM staged_logs sl
+                JOIN blocks ON blocks.node_id = $1 AND blocks.block_number = sl.block_number
+                JOIN transactions ON transactions.block_id = blocks.id AND transactions.tx_hash = sl.receipt_tx_hash
+                JOIN receipts ON receipts.transaction_id = transactions.id
+                ON CONFLICT (node_id, block_number, index)
+                  DO UPDATE
+                    SET address = EXCLUDED.address,
+                        tx_hash = EXCLUDED.tx_hash,
+                        topic0 = EXCLUDED.topic0,
+                        topic1 = EXCLUDED.topic1,
+                        topic2 = EXCLUDED.topic2,
+                        topic3 = EXCLUDED.topic3,
+                        data = EXCLUDED.data,
+                        receipt_id = EXCLUDED.receipt_id,
+                        block_id = EXCLUDED.block_id`,
+		repository.nodeId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE logs
+                SET block_id = blocks.id
+                FROM staged_blocks sb
+                JOIN blocks ON blocks.node_id = $1 AND blocks.block_number = sb.block_number
+                WHERE logs.node_id = $1 AND logs.block_number = sb.block_number`,
+		repository.nodeId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE contract_state
+                SET block_id = blocks.id
+                FROM staged_blocks sb
+                JOIN blocks ON blocks.node_id = $1 AND blocks.block_number = sb.block_number
+                WHERE contract_state.node_id = $1 AND contract_state.block_number = sb.block_number`,
+		repository.nodeId)
+	return err
+}
@@ -92,6 +92,32 @@ var _ = Describe("Postgres repository", func() {
 		Expect(savedBlock).To(BeNil())
 	})
 
+	It("does not commit block or transactions if receipt is invalid", func() {
+		//badContractAddress violates db contract_address field length
+		badContractAddress := fmt.Sprintf("x %s", strings.Repeat("1", 100))
+		badReceipt := core.Receipt{
+			ContractAddress: badContractAddress,
+			TxHash:          "x123",
+		}
+		transaction := core.Transaction{Hash: "x123", Receipt: badReceipt}
+		block := core.Block{
+			Number:       123,
+			Transactions: []core.Transaction{transaction},
+		}
+		cfg, _ := config.NewConfig("private")
+		node := core.Node{GenesisBlock: "GENESIS", NetworkId: 1}
+		repository, _ := repositories.NewPostgres(cfg.Database, node)
+
+		err1 := repository.CreateOrUpdateBlock(block)
+		savedBlock, err2 := repository.FindBlockByNumber(123)
+		_, err3 := repository.FindReceipt("x123")
+
+		Expect(err1).To(HaveOccurred())
+		Expect(err2).To(HaveOccurred())
+		Expect(err3).To(HaveOccurred())
+		Expect(savedBlock).To(BeZero())
+	})
+
 	It("does not commit block or transactions if transaction is invalid", func() {
 		//badHash violates db To field length
 		badHash := fmt.Sprintf("x %s", strings.Repeat("1", 100))
@@ -112,4 +138,144 @@ var _ = Describe("Postgres repository", func() {
 		Expect(savedBlock).To(BeZero())
 	})
 
+	It("does not commit any of the blocks in a bulk insert if one is invalid", func() {
+		//badHash violates db To field length
+		badHash := fmt.Sprintf("x %s", strings.Repeat("1", 100))
+		blocks := []core.Block{
+			{Number: 123, Hash: "x123"},
+			{Number: 124, Hash: "x124", Transactions: []core.Transaction{{To: badHash}}},
+		}
+		cfg, _ := config.NewConfig("private")
+		node := core.Node{GenesisBlock: "GENESIS", NetworkId: 1}
+		repository, _ := repositories.NewPostgres(cfg.Database, node)
+
+		err1 := repository.CreateOrUpdateBlocks(blocks)
+		_, err2 := repository.FindBlockByNumber(123)
+		_, err3 := repository.FindBlockByNumber(124)
+
+		Expect(err1).To(HaveOccurred())
+		Expect(err2).To(HaveOccurred())
+		Expect(err3).To(HaveOccurred())
+	})
+
+	It("keeps the blocks a failed bulk insert would have replaced", func() {
+		//badHash violates db To field length
+		badHash := fmt.Sprintf("x %s", strings.Repeat("1", 100))
+		cfg, _ := config.NewConfig("private")
+		node := core.Node{GenesisBlock: "GENESIS", NetworkId: 1}
+		repository, _ := repositories.NewPostgres(cfg.Database, node)
+		testing.ClearData(repository)
+		repository.CreateOrUpdateBlock(core.Block{Number: 123, Hash: "x123"})
+
+		err1 := repository.CreateOrUpdateBlocks([]core.Block{
+			{Number: 123, Hash: "xabc", Transactions: []core.Transaction{{To: badHash}}},
+		})
+		savedBlock, err2 := repository.FindBlockByNumber(123)
+
+		Expect(err1).To(HaveOccurred())
+		Expect(err2).NotTo(HaveOccurred())
+		Expect(savedBlock.Hash).To(Equal("x123"))
+		Expect(repository.FindOrphanedBlocks(123)).To(BeEmpty())
+		testing.ClearData(repository)
+	})
+
+	Describe("Event tables", func() {
+		var repository repositories.Postgres
+		var transferTable core.EventTable
+
+		BeforeEach(func() {
+			cfg, _ := config.NewConfig("private")
+			node := core.Node{GenesisBlock: "GENESIS", NetworkId: 1}
+			repository, _ = repositories.NewPostgres(cfg.Database, node)
+			testing.ClearData(repository)
+			transferTable = core.EventTable{
+				Name:         "test_transfer",
+				ContractHash: "x123",
+				EventName:    "Transfer",
+				Columns: []core.EventTableColumn{
+					{Name: "from", Type: "address", Indexed: true},
+					{Name: "to", Type: "address", Indexed: true},
+					{Name: "value", Type: "uint256", Indexed: false},
+				},
+			}
+			repository.CreateLogs([]core.Log{{BlockNumber: 1, Index: 0, Address: "x123", TxHash: "x456", Topics: map[int]string{}}})
+			repository.CreateDecodedEvents([]core.DecodedEvent{{
+				Address:     "x123",
+				BlockNumber: 1,
+				LogIndex:    0,
+				TxHash:      "x456",
+				Name:        "Transfer",
+				Arguments: []core.EventArgument{
+					{Name: "from", Type: "address", Indexed: true, Value: "x888"},
+					{Name: "to", Type: "address", Indexed: true, Value: "x999"},
+					{Name: "value", Type: "uint256", Indexed: false, Value: "1000"},
+				},
+			}})
+		})
+
+		AfterEach(func() {
+			repository.Db.MustExec("DROP TABLE IF EXISTS test_transfer")
+		})
+
+		It("creates a table filled with the decoded events", func() {
+			err := repository.CreateEventTable(transferTable)
+			Expect(err).ToNot(HaveOccurred())
+
+			var from, to, value string
+			var blockNumber int64
+			err = repository.Db.QueryRow(`SELECT block_number, "from", "to", "value" FROM test_transfer`).
+				Scan(&blockNumber, &from, &to, &value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(1)))
+			Expect(from).To(Equal("x888"))
+			Expect(to).To(Equal("x999"))
+			Expect(value).To(Equal("1000"))
+			Expect(repository.FindEventTables("x123")).To(Equal([]core.EventTable{transferTable}))
+		})
+
+		It("adds events decoded after the table was created", func() {
+			repository.CreateEventTable(transferTable)
+			repository.CreateLogs([]core.Log{{BlockNumber: 2, Index: 0, Address: "x123", TxHash: "x789", Topics: map[int]string{}}})
+			repository.CreateDecodedEvents([]core.DecodedEvent{{
+				Address:     "x123",
+				BlockNumber: 2,
+				LogIndex:    0,
+				Name:        "Transfer",
+				Arguments: []core.EventArgument{
+					{Name: "from", Type: "address", Indexed: true, Value: "x999"},
+					{Name: "to", Type: "address", Indexed: true, Value: "x888"},
+					{Name: "value", Type: "uint256", Indexed: false, Value: "5"},
+				},
+			}})
+
+			err := repository.UpdateEventTables("x123")
+
+			Expect(err).ToNot(HaveOccurred())
+			var count int
+			repository.Db.Get(&count, `SELECT COUNT(*) FROM test_transfer`)
+			Expect(count).To(Equal(2))
+		})
+
+		It("does not read the events again that the table was already filled from", func() {
+			repository.CreateEventTable(transferTable)
+			repository.Db.MustExec(`DELETE FROM test_transfer`)
+
+			err := repository.UpdateEventTables("x123")
+
+			Expect(err).ToNot(HaveOccurred())
+			var count int
+			repository.Db.Get(&count, `SELECT COUNT(*) FROM test_transfer`)
+			Expect(count).To(Equal(0))
+		})
+
+		It("finds logs that have not been decoded", func() {
+			repository.CreateLogs([]core.Log{{BlockNumber: 2, Index: 0, Address: "x123", TxHash: "x789", Topics: map[int]string{}}})
+
+			logs := repository.FindUndecodedLogs("x123")
+
+			Expect(len(logs)).To(Equal(1))
+			Expect(logs[0].BlockNumber).To(Equal(int64(2)))
+		})
+	})
+
 })
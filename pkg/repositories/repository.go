@@ -8,14 +8,38 @@ const (
 
 type Repository interface {
 	CreateOrUpdateBlock(block core.Block) error
+	CreateOrUpdateBlocks(blocks []core.Block) error
 	BlockCount() int
 	FindBlockByNumber(blockNumber int64) (core.Block, error)
+	FindBlockByHash(blockHash string) (core.Block, error)
+	FindOrphanedBlocks(blockNumber int64) []core.Block
 	MaxBlockNumber() int64
 	MissingBlockNumbers(startingBlockNumber int64, endingBlockNumber int64) []int64
 	CreateContract(contract core.Contract) error
 	ContractExists(contractHash string) bool
 	FindContract(contractHash string) (core.Contract, error)
+	FindWatchedContracts() []core.Contract
+	CreateContractState(states []core.ContractState) error
+	FindContractState(contractHash string, attributeName string) []core.ContractState
+	CreateDecodedCalls(calls []core.DecodedCall) error
+	FindDecodedCalls(contractHash string, methodName string) []core.DecodedCall
 	CreateLogs(log []core.Log) error
 	FindLogs(address string, blockNumber int64) []core.Log
+	FindLogsInRange(address string, startingBlockNumber int64, endingBlockNumber int64) []core.Log
+	FindLogsPage(address string, startingBlockNumber int64, endingBlockNumber int64, after core.LogPosition, limit int) []core.Log
+	FindLogCheckpoint(contractHash string, topicsKey string) (int64, error)
+	UpdateLogCheckpoint(contractHash string, topicsKey string, blockNumber int64) error
+	CreateFailedLogRange(logRange core.LogRange) error
+	FindFailedLogRanges(contractHash string, topicsKey string) []core.LogRange
+	DeleteFailedLogRange(logRange core.LogRange) error
+	CreateDecodedEvents(events []core.DecodedEvent) error
+	FindDecodedEvents(address string, eventName string) []core.DecodedEvent
+	FindDecodedEvent(blockNumber int64, logIndex int64) (core.DecodedEvent, error)
+	FindReceipt(txHash string) (core.Receipt, error)
+	FindDeploymentBlockNumber(contractHash string) (int64, error)
+	FindContractCreation(contractAddress string) (core.ContractCreation, error)
+	FindContractCreationsByCreator(creatorAddress string) []core.ContractCreation
+	FindTransactions(address string, limit int, offset int) []core.Transaction
+	TransactionCount(address string) int
 	SetBlocksStatus(chainHead int64)
 }
@@ -1,6 +1,7 @@
 package testing
 
 import (
+	"math/big"
 	"sort"
 	"strconv"
 
@@ -12,8 +13,17 @@ import (
 
 func ClearData(postgres repositories.Postgres) {
 	postgres.Db.MustExec("DELETE FROM watched_contracts")
+	postgres.Db.MustExec("DELETE FROM contract_state")
+	postgres.Db.MustExec("DELETE FROM log_checkpoints")
+	postgres.Db.MustExec("DELETE FROM failed_log_ranges")
+	postgres.Db.MustExec("DELETE FROM decoded_calls")
+	postgres.Db.MustExec("DELETE FROM contract_creations")
+	postgres.Db.MustExec("DELETE FROM receipts")
 	postgres.Db.MustExec("DELETE FROM transactions")
 	postgres.Db.MustExec("DELETE FROM blocks")
+	postgres.Db.MustExec("DELETE FROM orphaned_blocks")
+	postgres.Db.MustExec("DELETE FROM event_tables")
+	postgres.Db.MustExec("DELETE FROM decoded_events")
 	postgres.Db.MustExec("DELETE FROM logs")
 }
 
@@ -64,7 +74,7 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 			blockTime := int64(1508981640)
 			uncleHash := "x789"
 			blockSize := int64(1000)
-			difficulty := int64(10)
+			difficulty := big.NewInt(10)
 			block := core.Block{
 				Difficulty: difficulty,
 				GasLimit:   gasLimit,
@@ -146,6 +156,37 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 			Expect(savedBlock.Transactions[1].Hash).To(Equal("x9ab"))
 		})
 
+		It("records the replaced block as orphaned", func() {
+			blockOne := core.Block{
+				Number:     123,
+				Hash:       "xabc",
+				ParentHash: "x122",
+			}
+			blockTwo := core.Block{
+				Number:     123,
+				Hash:       "xdef",
+				ParentHash: "x122",
+			}
+
+			repository.CreateOrUpdateBlock(blockOne)
+			repository.CreateOrUpdateBlock(blockTwo)
+
+			orphanedBlocks := repository.FindOrphanedBlocks(123)
+			Expect(len(orphanedBlocks)).To(Equal(1))
+			Expect(orphanedBlocks[0].Number).To(Equal(int64(123)))
+			Expect(orphanedBlocks[0].Hash).To(Equal("xabc"))
+			Expect(orphanedBlocks[0].ParentHash).To(Equal("x122"))
+		})
+
+		It("does not record a block as orphaned when it is saved again", func() {
+			block := core.Block{Number: 123, Hash: "xabc"}
+
+			repository.CreateOrUpdateBlock(block)
+			repository.CreateOrUpdateBlock(block)
+
+			Expect(repository.FindOrphanedBlocks(123)).To(BeEmpty())
+		})
+
 		It(`does not replace blocks when block number is not unique
 			     but block number + node id is`, func() {
 			blockOne := core.Block{
@@ -174,13 +215,14 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 
 		It("saves the attributes associated to a transaction", func() {
 			gasLimit := int64(5000)
-			gasPrice := int64(3)
+			gasPrice := big.NewInt(3)
 			nonce := uint64(10000)
 			to := "1234567890"
 			from := "0987654321"
-			value := int64(10)
+			value := big.NewInt(10)
 			transaction := core.Transaction{
 				Hash:     "x1234",
+				Data:     []byte{0xa9, 0x05, 0x9c, 0xbb},
 				GasPrice: gasPrice,
 				GasLimit: gasLimit,
 				Nonce:    nonce,
@@ -199,6 +241,7 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 			Expect(len(savedBlock.Transactions)).To(Equal(1))
 			savedTransaction := savedBlock.Transactions[0]
 			Expect(savedTransaction.Hash).To(Equal(transaction.Hash))
+			Expect(savedTransaction.Data).To(Equal(transaction.Data))
 			Expect(savedTransaction.To).To(Equal(to))
 			Expect(savedTransaction.From).To(Equal(from))
 			Expect(savedTransaction.Nonce).To(Equal(nonce))
@@ -207,6 +250,192 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 			Expect(savedTransaction.Value).To(Equal(value))
 		})
 
+		It("saves values that overflow an int64", func() {
+			value, _ := new(big.Int).SetString("100000000000000000000", 10)
+			gasPrice, _ := new(big.Int).SetString("9223372036854775808", 10)
+			difficulty, _ := new(big.Int).SetString("1942399778958629", 10)
+			transaction := core.Transaction{
+				Hash:     "x1234",
+				GasPrice: gasPrice,
+				Value:    value,
+			}
+			block := core.Block{
+				Difficulty:   difficulty,
+				Number:       123,
+				Transactions: []core.Transaction{transaction},
+			}
+
+			repository.CreateOrUpdateBlock(block)
+
+			savedBlock, _ := repository.FindBlockByNumber(123)
+			Expect(savedBlock.Difficulty).To(Equal(difficulty))
+			savedTransaction := savedBlock.Transactions[0]
+			Expect(savedTransaction.GasPrice).To(Equal(gasPrice))
+			Expect(savedTransaction.Value).To(Equal(value))
+		})
+
+		It("finds a block by its hash", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 123, Hash: "x123"})
+			repository.CreateOrUpdateBlock(core.Block{Number: 124, Hash: "x124"})
+
+			savedBlock, err := repository.FindBlockByHash("x124")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(savedBlock.Number).To(Equal(int64(124)))
+		})
+
+		It("returns an error when no block has the hash", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 123, Hash: "x123"})
+
+			_, err := repository.FindBlockByHash("x999")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+	})
+
+	Describe("Saving blocks in bulk", func() {
+		It("saves each block with its transactions", func() {
+			blocks := []core.Block{
+				{Number: 1, Hash: "x1", Transactions: []core.Transaction{{Hash: "x1a", To: "x123"}}},
+				{Number: 2, Hash: "x2", Transactions: []core.Transaction{{Hash: "x2a", To: "x123"}, {Hash: "x2b", From: "x123"}}},
+			}
+
+			err := repository.CreateOrUpdateBlocks(blocks)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(repository.BlockCount()).To(Equal(2))
+			savedBlock, err := repository.FindBlockByNumber(2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(savedBlock.Hash).To(Equal("x2"))
+			Expect(len(savedBlock.Transactions)).To(Equal(2))
+			Expect(len(repository.FindTransactions("x123", 10, 0))).To(Equal(3))
+		})
+
+		It("saves the receipts and their logs", func() {
+			txHash := "0x97d99bc7729211111a21b12c933c949d4f31684f1d6954ff477d0477538ff017"
+			receipt := core.Receipt{
+				TxHash:  txHash,
+				GasUsed: 21000,
+				Logs: []core.Log{{
+					BlockNumber: 4745407,
+					Index:       0,
+					Address:     "0x8a4774fe82c63484afef97ca8d89a6ea5e21f973",
+					TxHash:      txHash,
+					Topics:      map[int]string{0: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+					Data:        "0x0000000000000000000000000000000000000000000000000000000000000001",
+				}},
+			}
+			transaction := core.Transaction{Hash: txHash, Receipt: receipt}
+
+			repository.CreateOrUpdateBlocks([]core.Block{{Number: 4745407, Hash: "x4745407", Transactions: []core.Transaction{transaction}}})
+
+			savedReceipt, err := repository.FindReceipt(txHash)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(savedReceipt.GasUsed).To(Equal(int64(21000)))
+			Expect(len(savedReceipt.Logs)).To(Equal(1))
+			logs := repository.FindLogs("0x8a4774fe82c63484afef97ca8d89a6ea5e21f973", 4745407)
+			Expect(len(logs)).To(Equal(1))
+			Expect(logs[0].Topics[0]).To(Equal("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"))
+		})
+
+		It("leaves blocks that are already saved with the same hash", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "x1"})
+
+			repository.CreateOrUpdateBlocks([]core.Block{{Number: 1, Hash: "x1"}, {Number: 2, Hash: "x2"}})
+
+			Expect(repository.BlockCount()).To(Equal(2))
+			Expect(repository.FindOrphanedBlocks(1)).To(BeEmpty())
+		})
+
+		It("replaces blocks that are saved with a different hash", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xabc"})
+
+			repository.CreateOrUpdateBlocks([]core.Block{{Number: 1, Hash: "xdef"}})
+
+			savedBlock, _ := repository.FindBlockByNumber(1)
+			Expect(repository.BlockCount()).To(Equal(1))
+			Expect(savedBlock.Hash).To(Equal("xdef"))
+			Expect(len(repository.FindOrphanedBlocks(1))).To(Equal(1))
+		})
+
+		It("links logs retrieved before the block so they are removed when it is replaced", func() {
+			repository.CreateLogs([]core.Log{{BlockNumber: 1, Index: 0, Address: "x123", TxHash: "x1a", Topics: map[int]string{}}})
+
+			repository.CreateOrUpdateBlocks([]core.Block{{Number: 1, Hash: "xabc"}})
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xdef"})
+
+			Expect(repository.FindLogs("x123", 1)).To(BeEmpty())
+		})
+	})
+
+	Describe("Finding transactions by address", func() {
+		BeforeEach(func() {
+			repository.CreateOrUpdateBlock(core.Block{
+				Number: 1,
+				Hash:   "x1",
+				Transactions: []core.Transaction{
+					{Hash: "x11", To: "x123", From: "x456"},
+					{Hash: "x12", To: "x789", From: "x789"},
+				},
+			})
+			repository.CreateOrUpdateBlock(core.Block{
+				Number: 2,
+				Hash:   "x2",
+				Transactions: []core.Transaction{
+					{Hash: "x22", To: "x456", From: "x123"},
+					{Hash: "x21", To: "x123", From: "x789"},
+				},
+			})
+		})
+
+		It("returns transactions sent to or from the address, most recent block first", func() {
+			transactions := repository.FindTransactions("x123", 10, 0)
+
+			var hashes []string
+			for _, transaction := range transactions {
+				hashes = append(hashes, transaction.Hash)
+			}
+			Expect(hashes).To(Equal([]string{"x21", "x22", "x11"}))
+		})
+
+		It("pages through the transactions", func() {
+			firstPage := repository.FindTransactions("x123", 2, 0)
+			secondPage := repository.FindTransactions("x123", 2, 2)
+			thirdPage := repository.FindTransactions("x123", 2, 4)
+
+			Expect(len(firstPage)).To(Equal(2))
+			Expect(len(secondPage)).To(Equal(1))
+			Expect(secondPage[0].Hash).To(Equal("x11"))
+			Expect(thirdPage).To(BeEmpty())
+		})
+
+		It("counts the transactions sent to or from the address", func() {
+			Expect(repository.TransactionCount("x123")).To(Equal(3))
+			Expect(repository.TransactionCount("x999")).To(Equal(0))
+		})
+
+		It("finds the transactions of an address whatever its case", func() {
+			repository.CreateOrUpdateBlock(core.Block{
+				Number:       3,
+				Hash:         "x3",
+				Transactions: []core.Transaction{{Hash: "x31", To: "0xAbCd", From: "x789"}},
+			})
+
+			Expect(len(repository.FindTransactions("0xabcd", 10, 0))).To(Equal(1))
+			Expect(len(repository.FindTransactions("0xABCD", 10, 0))).To(Equal(1))
+			Expect(repository.TransactionCount("0xabcd")).To(Equal(1))
+		})
+
+		It("does not find transactions saved by another node", func() {
+			nodeTwo := core.Node{
+				GenesisBlock: "0x456",
+				NetworkId:    1,
+			}
+			repositoryTwo := buildRepository(nodeTwo)
+
+			Expect(repositoryTwo.FindTransactions("x123", 10, 0)).To(BeEmpty())
+		})
 	})
 
 	Describe("The missing block numbers", func() {
@@ -369,6 +598,319 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 		})
 	})
 
+	Describe("Recording contract state", func() {
+		It("returns the watched contracts", func() {
+			repository.CreateContract(core.Contract{Hash: "x456", Abi: "{\"some\": \"json\"}"})
+			repository.CreateContract(core.Contract{Hash: "x123"})
+
+			contracts := repository.FindWatchedContracts()
+
+			Expect(contracts).To(Equal([]core.Contract{
+				{Hash: "x123"},
+				{Hash: "x456", Abi: "{\"some\": \"json\"}"},
+			}))
+		})
+
+		It("returns the deployment block of a watched contract", func() {
+			repository.CreateContract(core.Contract{Hash: "x123", DeploymentBlock: 4719568})
+
+			contract, err := repository.FindContract("x123")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contract.DeploymentBlock).To(Equal(int64(4719568)))
+			Expect(repository.FindWatchedContracts()[0].DeploymentBlock).To(Equal(int64(4719568)))
+		})
+
+		It("keeps the deployment block when the contract is saved again without it", func() {
+			repository.CreateContract(core.Contract{Hash: "x123", DeploymentBlock: 4719568})
+			repository.CreateContract(core.Contract{Hash: "x123", Abi: "{\"some\": \"json\"}"})
+
+			contract, _ := repository.FindContract("x123")
+
+			Expect(contract.DeploymentBlock).To(Equal(int64(4719568)))
+			Expect(contract.Abi).To(Equal("{\"some\": \"json\"}"))
+		})
+
+		It("returns the state of an attribute ordered by block number", func() {
+			repository.CreateContractState([]core.ContractState{
+				{ContractHash: "x123", BlockNumber: 2, AttributeName: "totalSupply", Value: "2000"},
+				{ContractHash: "x123", BlockNumber: 1, AttributeName: "totalSupply", Value: "1000"},
+				{ContractHash: "x123", BlockNumber: 1, AttributeName: "symbol", Value: "OMG"},
+				{ContractHash: "x456", BlockNumber: 1, AttributeName: "totalSupply", Value: "5"},
+			})
+
+			states := repository.FindContractState("x123", "totalSupply")
+
+			Expect(states).To(Equal([]core.ContractState{
+				{ContractHash: "x123", BlockNumber: 1, AttributeName: "totalSupply", Value: "1000"},
+				{ContractHash: "x123", BlockNumber: 2, AttributeName: "totalSupply", Value: "2000"},
+			}))
+		})
+
+		It("updates the state recorded at the same block", func() {
+			repository.CreateContractState([]core.ContractState{
+				{ContractHash: "x123", BlockNumber: 1, AttributeName: "totalSupply", Value: "1000"},
+			})
+			repository.CreateContractState([]core.ContractState{
+				{ContractHash: "x123", BlockNumber: 1, AttributeName: "totalSupply", Value: "1001"},
+			})
+
+			states := repository.FindContractState("x123", "totalSupply")
+
+			Expect(len(states)).To(Equal(1))
+			Expect(states[0].Value).To(Equal("1001"))
+		})
+
+		It("removes the state recorded at a block when the block is replaced", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xabc"})
+			repository.CreateContractState([]core.ContractState{
+				{ContractHash: "x123", BlockNumber: 1, AttributeName: "totalSupply", Value: "1000"},
+			})
+
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xdef"})
+
+			Expect(repository.FindContractState("x123", "totalSupply")).To(BeNil())
+		})
+	})
+
+	Describe("Tracking log backfill progress", func() {
+		It("returns an error when the contract has no checkpoint", func() {
+			_, err := repository.FindLogCheckpoint("x123", "")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns the last block number scanned for the contract", func() {
+			repository.UpdateLogCheckpoint("x123", "", 999)
+			repository.UpdateLogCheckpoint("x123", "", 1999)
+			repository.UpdateLogCheckpoint("x456", "", 5)
+
+			blockNumber, err := repository.FindLogCheckpoint("x123", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(1999)))
+		})
+
+		It("keeps a checkpoint per topics", func() {
+			repository.UpdateLogCheckpoint("x123", "xtransfer", 1999)
+
+			_, err := repository.FindLogCheckpoint("x123", "")
+			Expect(err).To(HaveOccurred())
+			blockNumber, err := repository.FindLogCheckpoint("x123", "xtransfer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(1999)))
+		})
+
+		It("does not move a checkpoint back", func() {
+			repository.UpdateLogCheckpoint("x123", "", 1999)
+			repository.UpdateLogCheckpoint("x123", "", 999)
+
+			blockNumber, _ := repository.FindLogCheckpoint("x123", "")
+
+			Expect(blockNumber).To(Equal(int64(1999)))
+		})
+
+		It("returns the failed ranges of the contract ordered by starting block number", func() {
+			repository.CreateFailedLogRange(core.LogRange{ContractHash: "x123", StartingBlockNumber: 1000, EndingBlockNumber: 1999})
+			repository.CreateFailedLogRange(core.LogRange{ContractHash: "x123", StartingBlockNumber: 0, EndingBlockNumber: 999})
+			repository.CreateFailedLogRange(core.LogRange{ContractHash: "x123", StartingBlockNumber: 0, EndingBlockNumber: 999})
+			repository.CreateFailedLogRange(core.LogRange{ContractHash: "x456", StartingBlockNumber: 0, EndingBlockNumber: 999})
+
+			logRanges := repository.FindFailedLogRanges("x123", "")
+
+			Expect(logRanges).To(Equal([]core.LogRange{
+				{ContractHash: "x123", StartingBlockNumber: 0, EndingBlockNumber: 999},
+				{ContractHash: "x123", StartingBlockNumber: 1000, EndingBlockNumber: 1999},
+			}))
+		})
+
+		It("returns the failed ranges of the topics", func() {
+			repository.CreateFailedLogRange(core.LogRange{ContractHash: "x123", TopicsKey: "xtransfer", StartingBlockNumber: 0, EndingBlockNumber: 999})
+			repository.CreateFailedLogRange(core.LogRange{ContractHash: "x123", StartingBlockNumber: 1000, EndingBlockNumber: 1999})
+
+			Expect(repository.FindFailedLogRanges("x123", "xtransfer")).To(Equal([]core.LogRange{
+				{ContractHash: "x123", TopicsKey: "xtransfer", StartingBlockNumber: 0, EndingBlockNumber: 999},
+			}))
+		})
+
+		It("removes a failed range", func() {
+			logRange := core.LogRange{ContractHash: "x123", StartingBlockNumber: 0, EndingBlockNumber: 999}
+			repository.CreateFailedLogRange(logRange)
+
+			err := repository.DeleteFailedLogRange(logRange)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repository.FindFailedLogRanges("x123", "")).To(BeEmpty())
+		})
+	})
+
+	Describe("Finding the deployment block of a contract", func() {
+		It("returns the block of the receipt that created the contract", func() {
+			repository.CreateOrUpdateBlock(core.Block{
+				Number: 3,
+				Hash:   "x3",
+				Transactions: []core.Transaction{{
+					Hash:    "x678",
+					Receipt: core.Receipt{TxHash: "x678", ContractAddress: "0xABC"},
+				}},
+			})
+
+			blockNumber, err := repository.FindDeploymentBlockNumber("0xabc")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(3)))
+		})
+
+		It("returns an error when no saved receipt created the contract", func() {
+			_, err := repository.FindDeploymentBlockNumber("0xabc")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Saving contract creations", func() {
+		creationBlock := func(number int64, txHash string, from string, contractAddress string) core.Block {
+			return core.Block{
+				Number: number,
+				Hash:   "x" + strconv.FormatInt(number, 10),
+				Transactions: []core.Transaction{{
+					Hash: txHash,
+					From: from,
+					Receipt: core.Receipt{
+						TxHash:           txHash,
+						ContractAddress:  contractAddress,
+						ContractCodeHash: "0xc0de",
+						Status:           1,
+					},
+				}},
+			}
+		}
+
+		It("finds the creation of a contract saved with its block", func() {
+			repository.CreateOrUpdateBlock(creationBlock(3, "x678", "0x123", "0xABC"))
+
+			creation, err := repository.FindContractCreation("0xabc")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creation).To(Equal(core.ContractCreation{
+				BlockNumber:     3,
+				TxHash:          "x678",
+				CreatorAddress:  "0x123",
+				ContractAddress: "0xABC",
+				CodeHash:        "0xc0de",
+			}))
+		})
+
+		It("saves the creations of blocks saved in bulk", func() {
+			repository.CreateOrUpdateBlocks([]core.Block{
+				creationBlock(3, "x678", "0x123", "0xabc"),
+				creationBlock(4, "x789", "0x123", "0xdef"),
+			})
+
+			creation, err := repository.FindContractCreation("0xdef")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creation.BlockNumber).To(Equal(int64(4)))
+			Expect(creation.TxHash).To(Equal("x789"))
+		})
+
+		It("finds the contracts created by an address in block order", func() {
+			repository.CreateOrUpdateBlock(creationBlock(5, "x789", "0x123", "0xdef"))
+			repository.CreateOrUpdateBlock(creationBlock(3, "x678", "0x123", "0xabc"))
+			repository.CreateOrUpdateBlock(creationBlock(4, "x456", "0x456", "0x999"))
+
+			creations := repository.FindContractCreationsByCreator("0x123")
+
+			Expect(len(creations)).To(Equal(2))
+			Expect(creations[0].ContractAddress).To(Equal("0xabc"))
+			Expect(creations[1].ContractAddress).To(Equal("0xdef"))
+		})
+
+		It("does not record failed deployments", func() {
+			block := creationBlock(3, "x678", "0x123", "0xabc")
+			block.Transactions[0].Receipt.Status = 0
+			repository.CreateOrUpdateBlock(block)
+
+			_, err := repository.FindContractCreation("0xabc")
+
+			Expect(err).To(HaveOccurred())
+			Expect(repository.FindContractCreationsByCreator("0x123")).To(BeEmpty())
+		})
+
+		It("removes the creation when its block is replaced", func() {
+			repository.CreateOrUpdateBlock(creationBlock(3, "x678", "0x123", "0xabc"))
+			replacement := core.Block{Number: 3, Hash: "x3-uncle"}
+			repository.CreateOrUpdateBlock(replacement)
+
+			_, err := repository.FindContractCreation("0xabc")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Saving receipts", func() {
+		It("returns the receipt when it exists", func() {
+			expected := core.Receipt{
+				Bloom:             "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+				ContractAddress:   "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",
+				CumulativeGasUsed: 7996119,
+				GasUsed:           21000,
+				StateRoot:         "0x88abf7e73128227370aa7baa3dd4e18d0af70e92ef1f9ef426942fbe2dddb733",
+				Status:            1,
+				TxHash:            "0xe340558980f89d5f86045ac11e5cc34e4bcec20f9f1e2a427aa39d87114e8223",
+			}
+			transaction := core.Transaction{
+				Hash:    expected.TxHash,
+				Receipt: expected,
+			}
+			block := core.Block{Transactions: []core.Transaction{transaction}}
+			repository.CreateOrUpdateBlock(block)
+
+			receipt, err := repository.FindReceipt("0xe340558980f89d5f86045ac11e5cc34e4bcec20f9f1e2a427aa39d87114e8223")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(receipt.Bloom).To(Equal(expected.Bloom))
+			Expect(receipt.TxHash).To(Equal(expected.TxHash))
+			Expect(receipt.CumulativeGasUsed).To(Equal(expected.CumulativeGasUsed))
+			Expect(receipt.GasUsed).To(Equal(expected.GasUsed))
+			Expect(receipt.StateRoot).To(Equal(expected.StateRoot))
+			Expect(receipt.Status).To(Equal(expected.Status))
+			Expect(receipt.ContractAddress).To(Equal(expected.ContractAddress))
+		})
+
+		It("returns ErrReceiptDoesNotExist when receipt does not exist", func() {
+			receipt, err := repository.FindReceipt("DOES NOT EXIST")
+			Expect(err).To(HaveOccurred())
+			Expect(receipt).To(BeZero())
+		})
+
+		It("saves the logs of the receipt", func() {
+			txHash := "0x97d99bc7729211111a21b12c933c949d4f31684f1d6954ff477d0477538ff017"
+			receipt := core.Receipt{
+				TxHash: txHash,
+				Logs: []core.Log{{
+					BlockNumber: 4745407,
+					Index:       0,
+					Address:     "0x8a4774fe82c63484afef97ca8d89a6ea5e21f973",
+					TxHash:      txHash,
+					Topics:      map[int]string{0: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+					Data:        "0x0000000000000000000000000000000000000000000000000000000000000001",
+				}},
+			}
+			transaction := core.Transaction{Hash: txHash, Receipt: receipt}
+			block := core.Block{Number: 4745407, Transactions: []core.Transaction{transaction}}
+			repository.CreateOrUpdateBlock(block)
+
+			savedReceipt, err := repository.FindReceipt(txHash)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(savedReceipt.Logs)).To(Equal(1))
+			Expect(savedReceipt.Logs[0].Address).To(Equal("0x8a4774fe82c63484afef97ca8d89a6ea5e21f973"))
+			Expect(savedReceipt.Logs[0].Topics[0]).To(Equal("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"))
+			Expect(savedReceipt.Logs[0].Data).To(Equal("0x0000000000000000000000000000000000000000000000000000000000000001"))
+		})
+	})
+
 	Describe("Saving logs", func() {
 		It("returns the log when it exists", func() {
 			repository.CreateLogs([]core.Log{{
@@ -423,6 +965,119 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 			Expect(log[0].Data).To(Equal("xXYZ"))
 		})
 
+		It("does not find logs saved by another node", func() {
+			repository.CreateLogs([]core.Log{{
+				BlockNumber: 1,
+				Index:       0,
+				Address:     "x123",
+				TxHash:      "x456",
+				Topics:      map[int]string{0: "x777", 1: "x888", 2: "x999"},
+				Data:        "xabc",
+			}},
+			)
+			nodeTwo := core.Node{
+				GenesisBlock: "0x456",
+				NetworkId:    1,
+			}
+			repositoryTwo := buildRepository(nodeTwo)
+
+			Expect(repositoryTwo.FindLogs("x123", 1)).To(BeNil())
+		})
+
+		It("keeps logs with the same block number and index from different nodes apart", func() {
+			nodeTwo := core.Node{
+				GenesisBlock: "0x456",
+				NetworkId:    1,
+			}
+			repositoryTwo := buildRepository(nodeTwo)
+			repository.CreateLogs([]core.Log{{
+				BlockNumber: 1,
+				Index:       0,
+				Address:     "x123",
+				TxHash:      "x456",
+				Topics:      map[int]string{0: "x777", 1: "x888", 2: "x999"},
+				Data:        "xabc",
+			}},
+			)
+			repositoryTwo.CreateLogs([]core.Log{{
+				BlockNumber: 1,
+				Index:       0,
+				Address:     "x123",
+				TxHash:      "x789",
+				Topics:      map[int]string{0: "x777", 1: "x888", 2: "x999"},
+				Data:        "xdef",
+			}},
+			)
+
+			logs := repository.FindLogs("x123", 1)
+			logsTwo := repositoryTwo.FindLogs("x123", 1)
+
+			Expect(len(logs)).To(Equal(1))
+			Expect(logs[0].Data).To(Equal("xabc"))
+			Expect(len(logsTwo)).To(Equal(1))
+			Expect(logsTwo[0].Data).To(Equal("xdef"))
+		})
+
+		It("removes the logs of a block when the block is replaced", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xabc"})
+			repository.CreateLogs([]core.Log{{
+				BlockNumber: 1,
+				Index:       0,
+				Address:     "x123",
+				TxHash:      "x456",
+				Topics:      map[int]string{0: "x777", 1: "x888", 2: "x999"},
+				Data:        "xabc",
+			}},
+			)
+
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xdef"})
+
+			Expect(repository.FindLogs("x123", 1)).To(BeNil())
+		})
+
+		It("removes logs saved before their block when the block is replaced", func() {
+			repository.CreateLogs([]core.Log{{
+				BlockNumber: 1,
+				Index:       0,
+				Address:     "x123",
+				TxHash:      "x456",
+				Topics:      map[int]string{0: "x777", 1: "x888", 2: "x999"},
+				Data:        "xabc",
+			}},
+			)
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xabc"})
+			Expect(len(repository.FindLogs("x123", 1))).To(Equal(1))
+
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xdef"})
+
+			Expect(repository.FindLogs("x123", 1)).To(BeNil())
+		})
+
+		It("finds the logs of the receipts in a block", func() {
+			receipt := core.Receipt{
+				TxHash: "x456",
+				Logs: []core.Log{{
+					BlockNumber: 1,
+					Index:       0,
+					Address:     "x123",
+					TxHash:      "x456",
+					Topics:      map[int]string{0: "x777"},
+					Data:        "xabc",
+				}},
+			}
+			block := core.Block{
+				Number:       1,
+				Hash:         "xabc",
+				Transactions: []core.Transaction{{Hash: "x456", Receipt: receipt}},
+			}
+
+			repository.CreateOrUpdateBlock(block)
+
+			logs := repository.FindLogs("x123", 1)
+			Expect(len(logs)).To(Equal(1))
+			Expect(logs[0].Data).To(Equal("xabc"))
+		})
+
 		It("filters to the correct block number and address", func() {
 			repository.CreateLogs([]core.Log{{
 				BlockNumber: 1,
@@ -481,5 +1136,225 @@ func AssertRepositoryBehavior(buildRepository func(node core.Node) repositories.
 					{blockNumber: 1, Index: 1}},
 			))
 		})
+
+		It("finds the logs of an address within a block range", func() {
+			repository.CreateLogs([]core.Log{
+				{BlockNumber: 3, Index: 1, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 1, Index: 0, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 2, Index: 2, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 2, Index: 1, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 2, Index: 3, Address: "x999", TxHash: "x456"},
+			})
+
+			logs := repository.FindLogsInRange("x123", 2, 3)
+
+			type logIndex struct {
+				blockNumber int64
+				Index       int64
+			}
+			var logIndexes []logIndex
+			for _, log := range logs {
+				logIndexes = append(logIndexes, logIndex{log.BlockNumber, log.Index})
+			}
+			Expect(logIndexes).To(Equal([]logIndex{
+				{blockNumber: 2, Index: 1},
+				{blockNumber: 2, Index: 2},
+				{blockNumber: 3, Index: 1},
+			}))
+		})
+
+		It("pages through the logs of an address after a position", func() {
+			repository.CreateLogs([]core.Log{
+				{BlockNumber: 3, Index: 1, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 1, Index: 0, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 2, Index: 2, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 2, Index: 1, Address: "x123", TxHash: "x456"},
+				{BlockNumber: 2, Index: 3, Address: "x999", TxHash: "x456"},
+				{BlockNumber: 4, Index: 0, Address: "x123", TxHash: "x456"},
+			})
+
+			firstPage := repository.FindLogsPage("x123", 1, 3, core.LogPosition{BlockNumber: -1, Index: -1}, 2)
+			secondPage := repository.FindLogsPage("x123", 1, 3, core.LogPosition{BlockNumber: 2, Index: 1}, 2)
+
+			var positions []core.LogPosition
+			for _, log := range append(firstPage, secondPage...) {
+				positions = append(positions, core.LogPosition{BlockNumber: log.BlockNumber, Index: log.Index})
+			}
+			Expect(positions).To(Equal([]core.LogPosition{
+				{BlockNumber: 1, Index: 0},
+				{BlockNumber: 2, Index: 1},
+				{BlockNumber: 2, Index: 2},
+				{BlockNumber: 3, Index: 1},
+			}))
+		})
+	})
+	Describe("Saving decoded events", func() {
+		var transferLog core.Log
+		var transferEvent core.DecodedEvent
+
+		BeforeEach(func() {
+			transferLog = core.Log{
+				BlockNumber: 1,
+				Index:       0,
+				Address:     "x123",
+				TxHash:      "x456",
+				Topics:      map[int]string{0: "x777", 1: "x888", 2: "x999"},
+				Data:        "xabc",
+			}
+			transferEvent = core.DecodedEvent{
+				Address:     "x123",
+				BlockNumber: 1,
+				LogIndex:    0,
+				TxHash:      "x456",
+				Name:        "Transfer",
+				Arguments: []core.EventArgument{
+					{Name: "from", Type: "address", Indexed: true, Value: "x888"},
+					{Name: "to", Type: "address", Indexed: true, Value: "x999"},
+					{Name: "value", Type: "uint256", Indexed: false, Value: "1000"},
+				},
+			}
+		})
+
+		It("returns the decoded event when it exists", func() {
+			repository.CreateLogs([]core.Log{transferLog})
+
+			err := repository.CreateDecodedEvents([]core.DecodedEvent{transferEvent})
+			Expect(err).ToNot(HaveOccurred())
+
+			events := repository.FindDecodedEvents("x123", "Transfer")
+			Expect(events).To(Equal([]core.DecodedEvent{transferEvent}))
+		})
+
+		It("does not save a decoded event without its log", func() {
+			err := repository.CreateDecodedEvents([]core.DecodedEvent{transferEvent})
+
+			Expect(err).To(HaveOccurred())
+			Expect(repository.FindDecodedEvents("x123", "Transfer")).To(BeNil())
+		})
+
+		It("filters to the correct address and event name", func() {
+			approvalLog := transferLog
+			approvalLog.Index = 1
+			approvalEvent := transferEvent
+			approvalEvent.LogIndex = 1
+			approvalEvent.Name = "Approval"
+			repository.CreateLogs([]core.Log{transferLog, approvalLog})
+
+			repository.CreateDecodedEvents([]core.DecodedEvent{transferEvent, approvalEvent})
+
+			Expect(repository.FindDecodedEvents("x123", "Transfer")).To(Equal([]core.DecodedEvent{transferEvent}))
+			Expect(repository.FindDecodedEvents("x123", "Approval")).To(Equal([]core.DecodedEvent{approvalEvent}))
+			Expect(repository.FindDecodedEvents("x456", "Transfer")).To(BeNil())
+		})
+
+		It("finds the decoded event of a log", func() {
+			repository.CreateLogs([]core.Log{transferLog})
+			repository.CreateDecodedEvents([]core.DecodedEvent{transferEvent})
+
+			event, err := repository.FindDecodedEvent(1, 0)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event).To(Equal(transferEvent))
+		})
+
+		It("returns an error when a log has no decoded event", func() {
+			repository.CreateLogs([]core.Log{transferLog})
+
+			_, err := repository.FindDecodedEvent(1, 0)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("removes decoded events when their block is replaced", func() {
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xabc"})
+			repository.CreateLogs([]core.Log{transferLog})
+			repository.CreateDecodedEvents([]core.DecodedEvent{transferEvent})
+
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xdef"})
+
+			Expect(repository.FindDecodedEvents("x123", "Transfer")).To(BeNil())
+		})
+	})
+	Describe("Saving decoded calls", func() {
+		var transferCall core.DecodedCall
+
+		BeforeEach(func() {
+			transferCall = core.DecodedCall{
+				TxHash: "x456",
+				To:     "x123",
+				From:   "x789",
+				Method: "transfer",
+				Arguments: []core.CallArgument{
+					{Name: "_to", Type: "address", Value: "x888"},
+					{Name: "_value", Type: "uint256", Value: "1000"},
+				},
+			}
+		})
+
+		It("returns the decoded call when it exists", func() {
+			repository.CreateOrUpdateBlock(core.Block{
+				Number:       1,
+				Hash:         "xabc",
+				Transactions: []core.Transaction{{Hash: "x456", To: "x123", From: "x789"}},
+			})
+
+			err := repository.CreateDecodedCalls([]core.DecodedCall{transferCall})
+			Expect(err).ToNot(HaveOccurred())
+
+			calls := repository.FindDecodedCalls("x123", "transfer")
+			Expect(calls).To(Equal([]core.DecodedCall{transferCall}))
+		})
+
+		It("finds the decoded calls of a contract whatever the case of its address", func() {
+			repository.CreateOrUpdateBlock(core.Block{
+				Number:       1,
+				Hash:         "xabc",
+				Transactions: []core.Transaction{{Hash: "x456", To: "0xAbCd", From: "x789"}},
+			})
+			transferCall.To = "0xAbCd"
+
+			repository.CreateDecodedCalls([]core.DecodedCall{transferCall})
+
+			Expect(len(repository.FindDecodedCalls("0xabcd", "transfer"))).To(Equal(1))
+			Expect(len(repository.FindDecodedCalls("0xABCD", "transfer"))).To(Equal(1))
+		})
+
+		It("does not save a decoded call without its transaction", func() {
+			err := repository.CreateDecodedCalls([]core.DecodedCall{transferCall})
+
+			Expect(err).To(HaveOccurred())
+			Expect(repository.FindDecodedCalls("x123", "transfer")).To(BeNil())
+		})
+
+		It("filters to the correct contract and method", func() {
+			approveCall := core.DecodedCall{TxHash: "x457", To: "x123", From: "x789", Method: "approve"}
+			repository.CreateOrUpdateBlock(core.Block{
+				Number: 1,
+				Hash:   "xabc",
+				Transactions: []core.Transaction{
+					{Hash: "x456", To: "x123", From: "x789"},
+					{Hash: "x457", To: "x123", From: "x789"},
+				},
+			})
+
+			repository.CreateDecodedCalls([]core.DecodedCall{transferCall, approveCall})
+
+			Expect(repository.FindDecodedCalls("x123", "transfer")).To(Equal([]core.DecodedCall{transferCall}))
+			Expect(len(repository.FindDecodedCalls("x123", "approve"))).To(Equal(1))
+			Expect(repository.FindDecodedCalls("x999", "transfer")).To(BeNil())
+		})
+
+		It("removes decoded calls when their block is replaced", func() {
+			repository.CreateOrUpdateBlock(core.Block{
+				Number:       1,
+				Hash:         "xabc",
+				Transactions: []core.Transaction{{Hash: "x456", To: "x123", From: "x789"}},
+			})
+			repository.CreateDecodedCalls([]core.DecodedCall{transferCall})
+
+			repository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xdef"})
+
+			Expect(repository.FindDecodedCalls("x123", "transfer")).To(BeNil())
+		})
 	})
 }